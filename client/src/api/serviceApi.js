@@ -1,7 +1,7 @@
 const API_BASE_URL = import.meta.env.VITE_API_BASE_URL;
 
 export async function fetchServices(token) {
-  const res = await fetch(`${API_BASE_URL}/user/get-services`, {
+  const res = await fetch(`${API_BASE_URL}/user/get-services?view=flat`, {
     headers: { Authorization: `Bearer ${token}` },
   });
   if (!res.ok) throw new Error('Failed to fetch services');
@@ -13,7 +13,7 @@ const STATUS_OPTIONS = [
   "identified",
   "monitoring",
   "resolved",
-  "maintainence",
+  "maintenance",
 ];
 
 const EditIncident = () => {
@@ -40,6 +40,7 @@ const EditIncident = () => {
         linked_services: (data.linked_services || []).map((s) => ({
           service_id: s.id,
           name: s.name,
+          impact: s.impact,
         })),
       });
     } catch (err) {
@@ -186,7 +187,12 @@ const EditIncident = () => {
                 );
                 setIncident((prev) => ({
                   ...prev,
-                  linked_services: selectedServices,
+                  linked_services: selectedServices.map((s) => ({
+                    ...s,
+                    impact: prev.linked_services.find(
+                      (l) => l.service_id === s.service_id
+                    )?.impact,
+                  })),
                 }));
               }}
             >
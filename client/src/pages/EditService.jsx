@@ -97,6 +97,7 @@ const EditService = () => {
         },
         body: JSON.stringify({
           id: parseInt(id),
+          version: service?.version,
           ...form,
         }),
       });
@@ -32,7 +32,7 @@ export default function GetServicesPage() {
     setError('');
     try {
       const token = await getToken();
-      const res = await fetch(`${API_BASE_URL}/user/get-services`, {
+      const res = await fetch(`${API_BASE_URL}/user/get-services?view=flat`, {
         headers: {
           Authorization: `Bearer ${token}`,
         },
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krnveersharma/Statuses/blobstore"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+const (
+	maxAttachmentBytes    = 10 << 20
+	maxAttachmentNameSize = 255
+)
+
+// allowedAttachmentTypes covers screenshots, graphs and log excerpts. The type
+// is sniffed from the content rather than taken from the client.
+var allowedAttachmentTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+}
+
+// UploadAttachment stores a file sent as the multipart field "file" against an
+// incident. An optional "update_id" form field ties it to a timeline entry.
+func (a *Api) UploadAttachment(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	incidentID := ctx.Param("id")
+	if _, err := strconv.Atoi(incidentID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident id"})
+		return
+	}
+
+	// Leave headroom for the multipart envelope around the file itself.
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxAttachmentBytes+1<<20)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Attachment is larger than 10 MB"})
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing file", "details": err.Error()})
+		}
+		return
+	}
+	if fileHeader.Size > maxAttachmentBytes {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Attachment is larger than 10 MB"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file", "details": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file", "details": err.Error()})
+		return
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !slices.Contains(allowedAttachmentTypes, mediaType) {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Unsupported attachment type: " + mediaType})
+		return
+	}
+
+	blobName, err := randomToken()
+	if err != nil {
+		log.Printf("[UploadAttachment] Failed to generate storage key: %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store attachment"})
+		return
+	}
+
+	attachment := Schemas.Attachment{
+		IncidentID:     incidentID,
+		FileName:       attachmentName(fileHeader.Filename),
+		ContentType:    contentType,
+		SizeBytes:      fileHeader.Size,
+		StorageKey:     "incidents/" + incidentID + "/" + blobName,
+		CreatedByClerk: clerkUser.ID,
+	}
+	if updateID := ctx.PostForm("update_id"); updateID != "" {
+		if _, err := strconv.Atoi(updateID); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid update id"})
+			return
+		}
+		attachment.UpdateID = &updateID
+	}
+
+	if err := a.Blobs.Put(ctx, attachment.StorageKey, io.MultiReader(bytes.NewReader(head), file)); err != nil {
+		log.Printf("[UploadAttachment] Failed to store blob: %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store attachment"})
+		return
+	}
+
+	saved, err := dbrequests.AddAttachment(a.DB, clerkUser.Org.ID, attachment)
+	if err != nil {
+		if deleteErr := a.Blobs.Delete(ctx, attachment.StorageKey); deleteErr != nil {
+			log.Printf("[UploadAttachment] Failed to clean up blob %s: %v\n", attachment.StorageKey, deleteErr)
+		}
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Incident or update not found"})
+		} else {
+			log.Printf("[UploadAttachment] %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save attachment", "details": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, saved)
+}
+
+func (a *Api) DownloadAttachment(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	attachment, err := dbrequests.GetAttachment(a.DB, ctx.Param("id"), ctx.Param("attachmentId"), clerkUser.Org.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		} else {
+			log.Println("error in fetching attachment:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachment"})
+		}
+		return
+	}
+
+	blob, err := a.Blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Attachment content is missing"})
+		} else {
+			log.Println("error in reading attachment blob:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read attachment"})
+		}
+		return
+	}
+	defer blob.Close()
+
+	ctx.DataFromReader(http.StatusOK, attachment.SizeBytes, attachment.ContentType, blob, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (a *Api) DeleteAttachment(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	attachment, err := dbrequests.GetAttachment(a.DB, ctx.Param("id"), ctx.Param("attachmentId"), clerkUser.Org.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		} else {
+			log.Println("error in fetching attachment:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachment"})
+		}
+		return
+	}
+
+	if err := dbrequests.DeleteAttachment(a.DB, attachment.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete attachment", "details": err.Error()})
+		return
+	}
+	a.deleteBlobs(ctx, []Schemas.Attachment{attachment})
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+// deleteBlobs removes stored content after its metadata rows are gone. Failures
+// only leave orphaned files behind, so they are logged rather than returned.
+func (a *Api) deleteBlobs(ctx *gin.Context, attachments []Schemas.Attachment) {
+	for _, attachment := range attachments {
+		if err := a.Blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			log.Printf("Failed to delete attachment blob %s: %v\n", attachment.StorageKey, err)
+		}
+	}
+}
+
+func attachmentName(name string) string {
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name == "/" || name == "." {
+		name = "attachment"
+	}
+	if len(name) > maxAttachmentNameSize {
+		name = name[len(name)-maxAttachmentNameSize:]
+	}
+	return name
+}
+
+// randomToken returns 16 random bytes in hex, for storage keys and secrets
+// handed to clients.
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+const (
+	badgeStyleFlat        = "flat"
+	badgeStyleForTheBadge = "for-the-badge"
+
+	// maxBadgeLabel caps ?label= so a badge cannot be made arbitrarily wide.
+	maxBadgeLabel = 50
+)
+
+// GetOrgBadge serves a badge with the worst status among the org's visible
+// services.
+func (a *Api) GetOrgBadge(ctx *gin.Context) {
+	a.serveBadge(ctx, func(services []Schemas.Service) (string, Schemas.ServiceStatus, bool) {
+		status := Schemas.ServiceOperational
+		for _, service := range services {
+			if service.Status.Worse(status) {
+				status = service.Status
+			}
+		}
+		return "status", status, true
+	})
+}
+
+// GetServiceBadge serves a badge with the status of the visible service
+// named by :slug.
+func (a *Api) GetServiceBadge(ctx *gin.Context) {
+	a.serveBadge(ctx, func(services []Schemas.Service) (string, Schemas.ServiceStatus, bool) {
+		for _, service := range services {
+			if service.Slug == ctx.Param("slug") {
+				return service.Name, service.Status, true
+			}
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return "", "", false
+	})
+}
+
+// serveBadge validates ?style= and ?label=, loads the org's visible services
+// and renders the label and status pick returns from them. pick writes its
+// own error response and returns false when it fails.
+func (a *Api) serveBadge(ctx *gin.Context, pick func([]Schemas.Service) (string, Schemas.ServiceStatus, bool)) {
+	style := ctx.DefaultQuery("style", badgeStyleFlat)
+	if style != badgeStyleFlat && style != badgeStyleForTheBadge {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "style must be flat or for-the-badge"})
+		return
+	}
+	customLabel := ctx.Query("label")
+	if utf8.RuneCountInString(customLabel) > maxBadgeLabel {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": fmt.Sprintf("label must be at most %d characters", maxBadgeLabel)})
+		return
+	}
+
+	a.servePublic(ctx, "image/svg+xml; charset=utf-8", func() ([]byte, bool) {
+		orgID, ok := a.publicOrg(ctx)
+		if !ok {
+			return nil, false
+		}
+
+		services, err := dbrequests.GetServicesForOrg(a.DB, orgID, nil)
+		if err != nil {
+			log.Println("Error in fetching services:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+			return nil, false
+		}
+		visible := make([]Schemas.Service, 0, len(services))
+		for _, service := range services {
+			if service.Hidden == nil || !*service.Hidden {
+				visible = append(visible, service)
+			}
+		}
+
+		label, status, ok := pick(visible)
+		if !ok {
+			return nil, false
+		}
+		if customLabel != "" {
+			label = customLabel
+		}
+		info := status.Info()
+		return []byte(renderBadge(style, label, info.Label, info.Color)), true
+	})
+}
+
+// renderBadge draws a shields.io-style badge: label on grey, value on color.
+// Text widths are estimated, as the SVG is drawn without font metrics.
+func renderBadge(style, label, value, color string) string {
+	fontSize, height, textY, weight, spacing, padding := 11.0, 20, 14, "normal", 0.0, 6.0
+	if style == badgeStyleForTheBadge {
+		label, value = strings.ToUpper(label), strings.ToUpper(value)
+		fontSize, height, textY, weight, spacing, padding = 10, 28, 18, "bold", 1.25, 9
+	}
+
+	labelWidth := math.Round(textWidth(label, fontSize, spacing) + 2*padding)
+	valueWidth := math.Round(textWidth(value, fontSize, spacing) + 2*padding)
+	width := labelWidth + valueWidth
+	title := html.EscapeString(label + ": " + value)
+	label, value = html.EscapeString(label), html.EscapeString(value)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%d" role="img" aria-label="%s">`, width, height, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+	if style == badgeStyleFlat {
+		b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+		fmt.Fprintf(&b, `<clipPath id="r"><rect width="%g" height="%d" rx="3" fill="#fff"/></clipPath>`, width, height)
+		b.WriteString(`<g clip-path="url(#r)">`)
+	} else {
+		b.WriteString(`<g>`)
+	}
+	fmt.Fprintf(&b, `<rect width="%g" height="%d" fill="#555"/>`, labelWidth, height)
+	fmt.Fprintf(&b, `<rect x="%g" width="%g" height="%d" fill="%s"/>`, labelWidth, valueWidth, height, color)
+	if style == badgeStyleFlat {
+		fmt.Fprintf(&b, `<rect width="%g" height="%d" fill="url(#s)"/>`, width, height)
+	}
+	b.WriteString(`</g>`)
+	fmt.Fprintf(&b, `<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="%g" font-weight="%s" letter-spacing="%g">`, fontSize, weight, spacing)
+	for _, text := range []struct {
+		x     float64
+		value string
+	}{{labelWidth / 2, label}, {labelWidth + valueWidth/2, value}} {
+		if style == badgeStyleFlat {
+			fmt.Fprintf(&b, `<text x="%g" y="%d" fill="#010101" fill-opacity=".3">%s</text>`, text.x, textY+1, text.value)
+		}
+		fmt.Fprintf(&b, `<text x="%g" y="%d">%s</text>`, text.x, textY, text.value)
+	}
+	b.WriteString(`</g></svg>`)
+
+	return b.String()
+}
+
+// textWidth estimates the rendered width of s in Verdana at size px.
+func textWidth(s string, size, spacing float64) float64 {
+	width := 0.0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("iljtfI.,:;'!| ", r):
+			width += 0.35 * size
+		case strings.ContainsRune("mwMW", r):
+			width += 0.95 * size
+		case r >= 'A' && r <= 'Z':
+			width += 0.72 * size
+		default:
+			width += 0.62 * size
+		}
+		width += spacing
+	}
+	return width
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+func (a *Api) GetEscalationPolicy(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	policy, err := dbrequests.GetEscalationPolicy(a.DB, clerkUser.Org.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "No escalation policy configured"})
+		} else {
+			log.Println("error in fetching escalation policy:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch escalation policy"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
+
+// SaveEscalationPolicy replaces the org's escalation policy.
+func (a *Api) SaveEscalationPolicy(ctx *gin.Context) {
+	var policy Schemas.EscalationPolicy
+
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	if err := ctx.ShouldBindJSON(&policy); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if err := policy.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	policy, err := dbrequests.SaveEscalationPolicy(a.DB, clerkUser.Org.ID, policy)
+	if err == dbrequests.ErrUnknownEscalationStep {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Println("error in saving escalation policy:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save escalation policy"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
+
+func (a *Api) DeleteEscalationPolicy(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	if err := dbrequests.DeleteEscalationPolicy(a.DB, clerkUser.Org.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete escalation policy", "details": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Escalation policy deleted successfully"})
+}
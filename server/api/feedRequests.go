@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/xml"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// feedLimit is how many incidents a feed lists.
+const feedLimit = 50
+
+// incidentFeed is what both feed formats are rendered from.
+type incidentFeed struct {
+	id        string
+	title     string
+	link      string
+	self      string
+	updated   time.Time
+	incidents []Schemas.PublicIncident
+}
+
+// GetIncidentFeedRSS serves the org's incident history as RSS 2.0.
+func (a *Api) GetIncidentFeedRSS(ctx *gin.Context) {
+	a.servePublic(ctx, "application/rss+xml; charset=utf-8", func() ([]byte, bool) {
+		feed, ok := a.loadIncidentFeed(ctx, "feed.rss")
+		if !ok {
+			return nil, false
+		}
+
+		rss := Schemas.RSSFeed{
+			Version: "2.0",
+			Atom:    "http://www.w3.org/2005/Atom",
+			Channel: Schemas.RSSChannel{
+				Title:         feed.title,
+				Link:          feed.link,
+				Description:   "Incidents and their updates for " + feed.title,
+				SelfLink:      Schemas.AtomLink{Href: feed.self, Rel: "self", Type: "application/rss+xml"},
+				LastBuildDate: feed.updated.Format(time.RFC1123Z),
+				Items:         []Schemas.RSSItem{},
+			},
+		}
+		for _, incident := range feed.incidents {
+			rss.Channel.Items = append(rss.Channel.Items, Schemas.RSSItem{
+				Title:       incident.Title,
+				Link:        feed.link,
+				Description: feedContent(incident),
+				GUID:        Schemas.RSSGUID{Value: feedEntryID(incident)},
+				PubDate:     feedUpdated(incident).Format(time.RFC1123Z),
+				Categories:  feedCategories(incident),
+			})
+		}
+		return marshalFeed(ctx, rss)
+	})
+}
+
+// GetIncidentFeedAtom serves the org's incident history as Atom.
+func (a *Api) GetIncidentFeedAtom(ctx *gin.Context) {
+	a.servePublic(ctx, "application/atom+xml; charset=utf-8", func() ([]byte, bool) {
+		feed, ok := a.loadIncidentFeed(ctx, "feed.atom")
+		if !ok {
+			return nil, false
+		}
+
+		atom := Schemas.AtomFeed{
+			ID:      feed.id,
+			Title:   feed.title,
+			Updated: feed.updated.Format(time.RFC3339),
+			Links: []Schemas.AtomLink{
+				{Href: feed.self, Rel: "self", Type: "application/atom+xml"},
+				{Href: feed.link, Rel: "alternate"},
+			},
+			Author:  Schemas.AtomAuthor{Name: ctx.Param("orgSlug")},
+			Entries: []Schemas.AtomEntry{},
+		}
+		for _, incident := range feed.incidents {
+			entry := Schemas.AtomEntry{
+				ID:        feedEntryID(incident),
+				Title:     incident.Title,
+				Updated:   feedUpdated(incident).Format(time.RFC3339),
+				Published: incident.StartedAt.UTC().Format(time.RFC3339),
+				Link:      Schemas.AtomLink{Href: feed.link, Rel: "alternate"},
+				Content:   Schemas.AtomText{Type: "html", Value: feedContent(incident)},
+			}
+			for _, category := range feedCategories(incident) {
+				entry.Categories = append(entry.Categories, Schemas.AtomCategory{Term: category})
+			}
+			atom.Entries = append(atom.Entries, entry)
+		}
+		return marshalFeed(ctx, atom)
+	})
+}
+
+// loadIncidentFeed resolves the org and the optional ?service= filter, given
+// as a service ID or slug, and loads the incidents to list. Hidden services
+// cannot be filtered on. name is the feed's file name, for its self link.
+func (a *Api) loadIncidentFeed(ctx *gin.Context, name string) (incidentFeed, bool) {
+	orgID, ok := a.publicOrg(ctx)
+	if !ok {
+		return incidentFeed{}, false
+	}
+
+	orgSlug := ctx.Param("orgSlug")
+	feed := incidentFeed{
+		id:    "urn:statuses:" + orgSlug + ":incidents",
+		title: orgSlug + " status",
+		link:  a.publicPageURL(orgSlug),
+		self:  a.Config.PublicURL + "/public/" + orgSlug + "/" + name,
+	}
+
+	serviceID := 0
+	if ref := ctx.Query("service"); ref != "" {
+		services, err := dbrequests.GetPublicServices(a.DB, orgID)
+		if err != nil {
+			log.Println("Error in fetching public services:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load feed"})
+			return incidentFeed{}, false
+		}
+		for _, service := range services {
+			if service.Slug == ref || strconv.Itoa(service.ID) == ref {
+				serviceID = service.ID
+				feed.id += ":service:" + strconv.Itoa(service.ID)
+				feed.title += " - " + service.Name
+				feed.self += "?service=" + url.QueryEscape(ref)
+				break
+			}
+		}
+		if serviceID == 0 {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+			return incidentFeed{}, false
+		}
+	}
+
+	incidents, err := dbrequests.GetPublicIncidentHistory(a.DB, orgID, serviceID, feedLimit)
+	if err != nil {
+		log.Println("Error in fetching incident history:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load feed"})
+		return incidentFeed{}, false
+	}
+	feed.incidents = incidents
+
+	for _, incident := range incidents {
+		feed.updated = latest(feed.updated, feedUpdated(incident))
+	}
+	if feed.updated.IsZero() {
+		feed.updated = time.Now()
+	}
+	feed.updated = feed.updated.UTC()
+
+	return feed, true
+}
+
+// feedEntryID is the permanent ID of an incident's entry. It does not depend
+// on the host or the org slug, so readers keep tracking the entry when either
+// changes.
+func feedEntryID(incident Schemas.PublicIncident) string {
+	return "urn:statuses:incident:" + incident.ID
+}
+
+// feedUpdated is when the incident was last edited or got a public update.
+func feedUpdated(incident Schemas.PublicIncident) time.Time {
+	updated := incident.UpdatedAt
+	if len(incident.Updates) > 0 {
+		updated = latest(updated, incident.Updates[0].CreatedAt)
+	}
+	return updated.UTC()
+}
+
+// feedCategories tags an entry with its status and the services it affects.
+func feedCategories(incident Schemas.PublicIncident) []string {
+	categories := []string{string(incident.Status)}
+	for _, service := range incident.AffectedServices {
+		categories = append(categories, service.Name)
+	}
+	return categories
+}
+
+// feedContent renders the incident as HTML: its public updates newest first,
+// then the affected services. Without updates the description stands in.
+func feedContent(incident Schemas.PublicIncident) string {
+	var b strings.Builder
+	for _, update := range incident.Updates {
+		b.WriteString("<p><strong>" + html.EscapeString(update.Status.Info().Label) + "</strong> - ")
+		b.WriteString(html.EscapeString(update.Body))
+		b.WriteString("<br><small>" + update.CreatedAt.UTC().Format("Jan 2, 15:04 MST") + "</small></p>")
+	}
+	if len(incident.Updates) == 0 && incident.Description != "" {
+		b.WriteString("<p>" + html.EscapeString(incident.Description) + "</p>")
+	}
+	if len(incident.AffectedServices) > 0 {
+		names := make([]string, 0, len(incident.AffectedServices))
+		for _, service := range incident.AffectedServices {
+			names = append(names, html.EscapeString(service.Name))
+		}
+		b.WriteString("<p>Affected: " + strings.Join(names, ", ") + "</p>")
+	}
+	return b.String()
+}
+
+// marshalFeed encodes a feed document, answering 500 on failure.
+func marshalFeed(ctx *gin.Context, v any) ([]byte, bool) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Println("Error marshaling XML:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load feed"})
+		return nil, false
+	}
+	return append([]byte(xml.Header), body...), true
+}
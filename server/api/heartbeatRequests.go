@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	"github.com/krnveersharma/Statuses/monitoring"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// ReceiveHeartbeat records a ping from a job. It is unauthenticated: the
+// token in the path is the credential.
+func (a *Api) ReceiveHeartbeat(ctx *gin.Context) {
+	heartbeat, err := dbrequests.RecordHeartbeatPing(ctx.Request.Context(), a.DB, ctx.Param("token"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Unknown heartbeat"})
+		} else {
+			log.Println("Error in recording heartbeat:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record heartbeat"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Heartbeat received"})
+
+	if heartbeat.Tripped {
+		monitoring.HeartbeatResumed(context.Background(), a.DB, heartbeat)
+	}
+}
+
+func (a *Api) GetHeartbeats(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	heartbeats, err := dbrequests.GetHeartbeatsForOrg(a.DB, clerkUser.Org.ID)
+	if err != nil {
+		log.Println("Error in fetching heartbeats:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch heartbeats"})
+		return
+	}
+	if heartbeats == nil {
+		heartbeats = []Schemas.Heartbeat{}
+	}
+
+	ctx.JSON(http.StatusOK, heartbeats)
+}
+
+// CreateHeartbeat creates a heartbeat and returns its token. The token is not
+// stored and cannot be shown again.
+func (a *Api) CreateHeartbeat(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	request, ok := a.bindHeartbeatRequest(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		log.Println("Error in generating heartbeat token:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create heartbeat"})
+		return
+	}
+
+	heartbeat, err := dbrequests.CreateHeartbeat(a.DB, request, token)
+	if err != nil {
+		log.Println("Error in creating heartbeat:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create heartbeat"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, heartbeat)
+}
+
+func (a *Api) EditHeartbeat(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	heartbeatID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid heartbeat id"})
+		return
+	}
+	request, ok := a.bindHeartbeatRequest(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+
+	heartbeat, released, err := dbrequests.UpdateHeartbeat(a.DB, heartbeatID, clerkUser.Org.ID, request)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Heartbeat not found"})
+		} else {
+			log.Println("Error in updating heartbeat:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update heartbeat"})
+		}
+		return
+	}
+	if released != nil {
+		reason := "had its failure status changed"
+		if heartbeat.ServiceID != released.ServiceID {
+			reason = "was moved to another service"
+		}
+		monitoring.HeartbeatReleased(context.Background(), a.DB, *released, reason)
+	}
+
+	ctx.JSON(http.StatusOK, heartbeat)
+}
+
+func (a *Api) DeleteHeartbeat(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	heartbeatID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid heartbeat id"})
+		return
+	}
+
+	heartbeat, err := dbrequests.DeleteHeartbeat(a.DB, heartbeatID, clerkUser.Org.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Heartbeat not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete heartbeat", "details": err.Error()})
+		}
+		return
+	}
+	if heartbeat.Tripped {
+		monitoring.HeartbeatDeleted(context.Background(), a.DB, heartbeat)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Heartbeat deleted successfully"})
+}
+
+func (a *Api) bindHeartbeatRequest(ctx *gin.Context, orgID string) (Schemas.HeartbeatRequest, bool) {
+	var request Schemas.HeartbeatRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		respondBindError(ctx, err)
+		return request, false
+	}
+	if err := request.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return request, false
+	}
+	serviceID, ok := a.serviceRef(ctx, orgID, request.Service)
+	request.ServiceID = serviceID
+	return request, ok
+}
@@ -3,8 +3,11 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
@@ -13,6 +16,8 @@ import (
 	"github.com/krnveersharma/Statuses/websocketsHandler"
 )
 
+var invalidSeverityMessage = "Severity must be one of: " + strings.Join(Schemas.IncidentSeverities, ", ")
+
 func (a *Api) CreateIncident(ctx *gin.Context) {
 	var incident Schemas.IncidentRequest
 
@@ -24,12 +29,26 @@ func (a *Api) CreateIncident(ctx *gin.Context) {
 	clerkUser := clerkUserRaw.(*middlewares.UserData)
 
 	if err := ctx.ShouldBindJSON(&incident); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		respondBindError(ctx, err)
 		return
 	}
 
-	if incident.Title == "" || incident.Status == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Title and status are required"})
+	if incident.Title == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+		return
+	}
+	if !requireIncidentStatus(ctx, incident.Status) {
+		return
+	}
+	if err := incident.Labels.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if !Schemas.ValidSeverity(incident.Severity) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidSeverityMessage})
+		return
+	}
+	if !a.resolveLinkedServices(ctx, clerkUser.Org.ID, incident.LinkedServices) {
 		return
 	}
 
@@ -53,11 +72,14 @@ func (a *Api) CreateIncident(ctx *gin.Context) {
 		Description:    incident.Description,
 		Status:         incident.Status,
 		LinkedServices: incident.LinkedServices,
+		Labels:         incident.Labels,
+		Severity:       incident.Severity,
 	}
 	a.UpdateIncidentUpdate(&newIncident, *clerkUser)
 
 	// Broadcast to websockets
 	websocketsHandler.CreateIncident(clerkUser.Org.ID, newIncident)
+	a.refreshAutoStatuses(clerkUser.Org.ID)
 
 	ctx.JSON(http.StatusCreated, gin.H{"message": "Incident created successfully"})
 }
@@ -70,7 +92,13 @@ func (a *Api) GetIncidents(ctx *gin.Context) {
 	}
 	clerkUser := clerkUserRaw.(*middlewares.UserData)
 
-	incidents, err := dbrequests.GetIncidentsForOrg(a.DB, clerkUser.Org.ID)
+	labels, err := labelFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	incidents, err := dbrequests.GetIncidentsForOrg(a.DB, clerkUser.Org.ID, labels)
 	if err != nil {
 		log.Println("Error in fetching incidents:", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch incidents"})
@@ -94,7 +122,7 @@ func (a *Api) GetIncidentByID(ctx *gin.Context) {
 		return
 	}
 
-	incident, err := dbrequests.GetIncidentByID(a.DB, incidentID, clerkUser.Org.ID)
+	details, err := a.loadIncidentDetails(incidentID, clerkUser.Org.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
@@ -105,28 +133,97 @@ func (a *Api) GetIncidentByID(ctx *gin.Context) {
 		return
 	}
 
+	if details.MergedInto != nil {
+		ctx.Header("Location", "/user/get-incident/"+*details.MergedInto)
+		ctx.JSON(http.StatusMovedPermanently, gin.H{
+			"message":     "Incident was merged into another incident",
+			"id":          details.ID,
+			"merged_into": *details.MergedInto,
+		})
+		return
+	}
+
+	setETag(ctx, details.Version)
+	ctx.JSON(http.StatusOK, details)
+}
+
+// incidentDetails is the full incident view returned by GetIncidentByID and
+// by edit conflicts.
+type incidentDetails struct {
+	*Schemas.Incident
+	LinkedServices []Schemas.Service `json:"linked_services"`
+	// PotentiallyImpacted are services depending on a linked service.
+	PotentiallyImpacted []Schemas.ImpactedService    `json:"potentially_impacted"`
+	Logs                []Schemas.IncidentUpdateData `json:"logs"`
+	Timings             *Schemas.IncidentTimings     `json:"timings,omitempty"`
+	Attachments         []Schemas.Attachment         `json:"attachments"`
+}
+
+func (a *Api) loadIncidentDetails(incidentID, orgID string) (*incidentDetails, error) {
+	incident, err := dbrequests.GetIncidentByID(a.DB, incidentID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
 	services, err := dbrequests.GetServicesAffected(a.DB, incidentID)
 	if err != nil {
-		log.Println("error in fetching services for incident:", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch linked services"})
-		return
+		return nil, fmt.Errorf("fetching linked services: %w", err)
 	}
 
-	logs, err := dbrequests.GetIncidentUpdates(a.DB, incidentID)
+	logs, err := dbrequests.GetIncidentUpdates(a.DB, incidentID, true)
 	if err != nil {
 		log.Println("error in fetching logs: ", err.Error())
 	}
 
-	response := struct {
-		*Schemas.Incident
-		LinkedServices []Schemas.Service            `json:"linked_services"`
-		Logs           []Schemas.IncidentUpdateData `json:"logs"`
-	}{
-		Incident:       incident,
-		LinkedServices: services,
-		Logs:           logs,
+	attachments, err := dbrequests.GetAttachments(a.DB, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching attachments: %w", err)
+	}
+
+	impacted, err := a.potentiallyImpacted(orgID, services)
+	if err != nil {
+		return nil, fmt.Errorf("computing blast radius: %w", err)
+	}
+
+	details := &incidentDetails{
+		Incident:            incident,
+		LinkedServices:      services,
+		PotentiallyImpacted: impacted,
+		Logs:                logs,
+		Attachments:         attachments,
+	}
+
+	timings, err := dbrequests.GetIncidentTimings(a.DB, incidentID, orgID)
+	if err != nil {
+		log.Println("error in computing incident timings: ", err.Error())
+	} else {
+		details.Timings = &timings
+	}
+
+	return details, nil
+}
+
+// editInstance converts the stored incident into the shape used for
+// timeline entries and websocket events.
+func (d *incidentDetails) editInstance() Schemas.EditInstance {
+	linked := make([]Schemas.LinkedServiceIn, 0, len(d.LinkedServices))
+	for _, service := range d.LinkedServices {
+		id := int32(service.ID)
+		linked = append(linked, Schemas.LinkedServiceIn{ServiceID: &id, Name: service.Name, Impact: Schemas.Impact(service.Impact)})
+	}
+	version := d.Version
+
+	return Schemas.EditInstance{
+		ID:             d.ID,
+		Title:          d.Title,
+		Description:    d.Description,
+		Status:         d.Status,
+		StartedAt:      d.StartedAt,
+		LinkedServices: linked,
+		Version:        &version,
+		Labels:         d.Labels,
+		Severity:       d.Severity,
 	}
-	ctx.JSON(http.StatusOK, response)
 }
 
 func (a *Api) EditIncident(ctx *gin.Context) {
@@ -142,24 +239,328 @@ func (a *Api) EditIncident(ctx *gin.Context) {
 
 	if err := ctx.ShouldBindJSON(&incident); err != nil {
 		log.Printf("[EditIncident] Failed to bind JSON: %v\n", err)
+		respondBindError(ctx, err)
+		return
+	}
+	if !requireIncidentStatus(ctx, incident.Status) {
+		return
+	}
+
+	if err := incident.Labels.Validate(); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
 		return
 	}
+	if !Schemas.ValidSeverity(incident.Severity) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidSeverityMessage})
+		return
+	}
+	if !a.resolveLinkedServices(ctx, clerkUser.Org.ID, incident.LinkedServices) {
+		return
+	}
 
-	log.Printf("[EditIncident] Editing incident ID: %s with title: %s\n", incident.ID, incident.Title)
+	bodyVersion := 0
+	if incident.Version != nil {
+		bodyVersion = *incident.Version
+	}
+	version, ok, err := expectedVersion(ctx, bodyVersion)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": "Missing version: send an If-Match header or a version field"})
+		return
+	}
 
-	if err := dbrequests.UpdateIncident(ctx, a.DB, clerkUser.Org.ID, incident); err != nil {
-		log.Printf("[EditIncident] %v\n", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update incident", "details": err.Error()})
+	log.Printf("[EditIncident] Editing incident ID: %s at version %d with title: %s\n", incident.ID, version, incident.Title)
+
+	before, err := dbrequests.GetServicesAffected(a.DB, incident.ID)
+	if err != nil {
+		log.Printf("[EditIncident] Failed to load linked services before edit: %v\n", err)
+	}
+
+	newVersion, err := dbrequests.UpdateIncident(ctx, a.DB, clerkUser.Org.ID, incident, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, dbrequests.ErrVersionConflict):
+			a.respondIncidentConflict(ctx, incident.ID, clerkUser.Org.ID)
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+		default:
+			log.Printf("[EditIncident] %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update incident", "details": err.Error()})
+		}
 		return
 	}
+	incident.Version = &newVersion
 
 	a.UpdateIncidentUpdate(&incident, *clerkUser)
+	if after, err := dbrequests.GetServicesAffected(a.DB, incident.ID); err == nil && before != nil {
+		a.recordImpactChanges(incident.ID, incident.Status, before, after, *clerkUser)
+	}
 
 	// Broadcast to websockets
 	websocketsHandler.UpdateIncident(incident.ID, clerkUser.Org.ID, incident)
+	a.refreshAutoStatuses(clerkUser.Org.ID)
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Incident updated successfully"})
+	setETag(ctx, newVersion)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Incident updated successfully", "version": newVersion})
+}
+
+// respondIncidentConflict answers a stale edit with 409 and the incident as
+// it is currently stored, so the client can rebase its changes.
+func (a *Api) respondIncidentConflict(ctx *gin.Context, incidentID, orgID string) {
+	current, err := a.loadIncidentDetails(incidentID, orgID)
+	if err != nil {
+		log.Printf("[EditIncident] Failed to load current incident after conflict: %v\n", err)
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Incident was modified by another request"})
+		return
+	}
+
+	setETag(ctx, current.Version)
+	ctx.JSON(http.StatusConflict, gin.H{"error": "Incident was modified by another request", "current": current})
+}
+
+// PatchIncident applies a JSON Merge Patch to an incident so callers can change
+// single fields or add/remove single service links. If-Match (or a version
+// member) is optional here; when given, stale patches are rejected with 409.
+func (a *Api) PatchIncident(ctx *gin.Context) {
+	var patch Schemas.IncidentPatch
+
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	incidentID := ctx.Param("id")
+	if incidentID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing incident ID"})
+		return
+	}
+
+	members, ok := bindMergePatch(ctx, &patch,
+		"title", "description", "status", "started_at",
+		"linked_services", "add_linked_services", "remove_linked_services", "service_impacts",
+		"labels", "severity", "version",
+	)
+	if !ok {
+		return
+	}
+
+	for _, key := range nullMembers(members, "title", "status", "started_at", "description", "severity", "linked_services", "labels") {
+		switch key {
+		case "description":
+			patch.Description = new(string)
+		case "severity":
+			patch.Severity = new(string)
+		case "linked_services":
+			patch.LinkedServices = &[]Schemas.LinkedServiceIn{}
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": key + " cannot be removed"})
+			return
+		}
+	}
+	if (patch.Title != nil && *patch.Title == "") || (patch.Status != nil && *patch.Status == "") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Title and status are required"})
+		return
+	}
+	labels, _ := patch.Labels.Split()
+	if err := labels.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if patch.Severity != nil && !Schemas.ValidSeverity(*patch.Severity) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidSeverityMessage})
+		return
+	}
+	if patch.LinkedServices != nil && !a.resolveLinkedServices(ctx, clerkUser.Org.ID, *patch.LinkedServices) {
+		return
+	}
+	// Unknown impacts fail to bind; an empty one cannot be set either.
+	for _, impact := range patch.ServiceImpacts {
+		if !impact.Valid() {
+			respondInvalidStatus(ctx, Schemas.NewInvalidImpact(string(impact)))
+			return
+		}
+	}
+
+	var guard *int
+	bodyVersion := 0
+	if patch.Version != nil {
+		bodyVersion = *patch.Version
+	}
+	version, ok, err := expectedVersion(ctx, bodyVersion)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if ok {
+		guard = &version
+	}
+
+	before, err := dbrequests.GetServicesAffected(a.DB, incidentID)
+	if err != nil {
+		log.Printf("[PatchIncident] Failed to load linked services before patch: %v\n", err)
+	}
+
+	newVersion, err := dbrequests.PatchIncident(ctx, a.DB, clerkUser.Org.ID, incidentID, patch, guard)
+	if err != nil {
+		switch {
+		case errors.Is(err, dbrequests.ErrVersionConflict):
+			a.respondIncidentConflict(ctx, incidentID, clerkUser.Org.ID)
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+		default:
+			log.Printf("[PatchIncident] %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update incident", "details": err.Error()})
+		}
+		return
+	}
+
+	details, err := a.loadIncidentDetails(incidentID, clerkUser.Org.ID)
+	if err != nil {
+		log.Printf("[PatchIncident] Failed to reload incident: %v\n", err)
+		a.refreshAutoStatuses(clerkUser.Org.ID)
+		ctx.JSON(http.StatusOK, gin.H{"message": "Incident updated successfully", "version": newVersion})
+		return
+	}
+	incident := details.editInstance()
+
+	a.UpdateIncidentUpdate(&incident, *clerkUser)
+	if before != nil {
+		a.recordImpactChanges(incidentID, incident.Status, before, details.LinkedServices, *clerkUser)
+	}
+
+	// Broadcast to websockets
+	websocketsHandler.UpdateIncident(incidentID, clerkUser.Org.ID, incident)
+	a.refreshAutoStatuses(clerkUser.Org.ID)
+
+	setETag(ctx, newVersion)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Incident updated successfully", "version": newVersion, "incident": incident})
+}
+
+// MergeIncidents folds duplicate incidents into a target incident. The merged
+// incidents are kept as redirects to the target.
+func (a *Api) MergeIncidents(ctx *gin.Context) {
+	var request Schemas.MergeIncidentsRequest
+
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if request.TargetID == "" || len(request.SourceIDs) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "target_id and source_ids are required"})
+		return
+	}
+
+	seen := map[string]bool{}
+	var sourceIDs []string
+	for _, id := range request.SourceIDs {
+		if id == request.TargetID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "An incident cannot be merged into itself"})
+			return
+		}
+		if !seen[id] {
+			seen[id] = true
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+
+	err := dbrequests.MergeIncidents(ctx, a.DB, clerkUser.Org.ID, request.TargetID, sourceIDs, clerkUser.ID, fullName(*clerkUser))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+		case errors.Is(err, dbrequests.ErrAlreadyMerged):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("[MergeIncidents] %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge incidents", "details": err.Error()})
+		}
+		return
+	}
+
+	details, err := a.loadIncidentDetails(request.TargetID, clerkUser.Org.ID)
+	if err != nil {
+		log.Printf("[MergeIncidents] Failed to reload target incident: %v\n", err)
+		a.refreshAutoStatuses(clerkUser.Org.ID)
+		ctx.JSON(http.StatusOK, gin.H{"message": "Incidents merged successfully"})
+		return
+	}
+
+	// Broadcast to websockets
+	websocketsHandler.MergeIncidents(request.TargetID, clerkUser.Org.ID, sourceIDs, details.editInstance())
+	a.refreshAutoStatuses(clerkUser.Org.ID)
+
+	setETag(ctx, details.Version)
+	ctx.JSON(http.StatusOK, details)
+}
+
+// AddIncidentNote lets responders write to an incident's timeline. Notes are
+// internal unless marked public; internal ones are only visible to org members.
+func (a *Api) AddIncidentNote(ctx *gin.Context) {
+	var request Schemas.IncidentNoteRequest
+
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	incidentID := ctx.Param("id")
+	if incidentID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing incident ID"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if strings.TrimSpace(request.Note) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Note is required"})
+		return
+	}
+	switch request.Visibility {
+	case "":
+		request.Visibility = Schemas.VisibilityInternal
+	case Schemas.VisibilityInternal, Schemas.VisibilityPublic:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Visibility must be internal or public"})
+		return
+	}
+
+	message, err := json.Marshal(Schemas.TimelineNote{Note: request.Note})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add note", "details": err.Error()})
+		return
+	}
+
+	update, err := dbrequests.AddIncidentNote(a.DB, incidentID, clerkUser.Org.ID, string(message), request.Visibility, clerkUser.ID, fullName(*clerkUser))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+		} else {
+			log.Printf("[AddIncidentNote] %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add note", "details": err.Error()})
+		}
+		return
+	}
+
+	// Broadcast to websockets
+	websocketsHandler.AddIncidentNote(incidentID, clerkUser.Org.ID, update)
+
+	ctx.JSON(http.StatusCreated, update)
 }
 
 func (a *Api) DeleteIncident(ctx *gin.Context) {
@@ -175,20 +576,35 @@ func (a *Api) DeleteIncident(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing incident id"})
 		return
 	}
-	err := dbrequests.DeleteIncident(a.DB, incidentId, clerkUser.Org.ID)
+	attachments, err := dbrequests.GetAttachments(a.DB, incidentId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete incident", "details": err.Error()})
+		return
+	}
+	err = dbrequests.DeleteIncident(a.DB, incidentId, clerkUser.Org.ID)
+	if err == sql.ErrNoRows {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete incident", "details": err.Error()})
 		return
 	}
+	a.deleteBlobs(ctx, attachments)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Incident deleted successfully"})
 
 	websocketsHandler.DeleteIncident(incidentId, clerkUser.Org.ID)
+	a.refreshAutoStatuses(clerkUser.Org.ID)
 }
 
 func (a *Api) UpdateIncidentUpdate(incident *Schemas.EditInstance, clerkUser middlewares.UserData) {
 	var linkedServices []string
 	for i := range incident.LinkedServices {
-		linkedServices = append(linkedServices, incident.LinkedServices[i].Name)
+		name := incident.LinkedServices[i].Name
+		if impact := incident.LinkedServices[i].Impact; impact != "" {
+			name += " (" + string(impact) + ")"
+		}
+		linkedServices = append(linkedServices, name)
 	}
 
 	jsonBytes, err := json.Marshal(Schemas.IncidentUpdate{
@@ -203,8 +619,61 @@ func (a *Api) UpdateIncidentUpdate(incident *Schemas.EditInstance, clerkUser mid
 
 	msg := string(jsonBytes)
 
-	err = dbrequests.UpdateIncidentUpdate(a.DB, msg, incident.ID, incident.Status, clerkUser.ID, *clerkUser.FirstName+" "+*clerkUser.LastName)
+	err = dbrequests.UpdateIncidentUpdate(a.DB, msg, incident.ID, incident.Status, clerkUser.ID, fullName(clerkUser))
 	if err != nil {
 		log.Println("Error in updating incident table:", err)
 	}
 }
+
+// fullName is the display name stored alongside timeline entries.
+func fullName(clerkUser middlewares.UserData) string {
+	var parts []string
+	if clerkUser.FirstName != nil {
+		parts = append(parts, *clerkUser.FirstName)
+	}
+	if clerkUser.LastName != nil {
+		parts = append(parts, *clerkUser.LastName)
+	}
+	return strings.Join(parts, " ")
+}
+
+// resolveLinkedServices sets the ServiceID of every link from the service it
+// names by ID or slug, answering 400 unless all are services of the org.
+func (a *Api) resolveLinkedServices(ctx *gin.Context, orgID string, links []Schemas.LinkedServiceIn) bool {
+	for i := range links {
+		serviceID, ok := a.serviceRef(ctx, orgID, links[i].Service)
+		if !ok {
+			return false
+		}
+		id := int32(serviceID)
+		links[i].ServiceID = &id
+	}
+	return true
+}
+
+// recordImpactChanges adds a timeline entry for every service that stayed
+// linked but whose impact changed between before and after.
+func (a *Api) recordImpactChanges(incidentID string, status Schemas.IncidentStatus, before, after []Schemas.Service, clerkUser middlewares.UserData) {
+	previous := map[int]string{}
+	for _, service := range before {
+		previous[service.ID] = service.Impact
+	}
+
+	for _, service := range after {
+		old, ok := previous[service.ID]
+		if !ok || old == service.Impact {
+			continue
+		}
+
+		msg, err := json.Marshal(Schemas.TimelineNote{
+			Note: fmt.Sprintf("Impact on %s changed from %s to %s", service.Name, old, service.Impact),
+		})
+		if err != nil {
+			log.Println("Error marshaling JSON:", err)
+			continue
+		}
+		if err := dbrequests.UpdateIncidentUpdate(a.DB, string(msg), incidentID, status, clerkUser.ID, fullName(clerkUser)); err != nil {
+			log.Println("Error in recording impact change:", err)
+		}
+	}
+}
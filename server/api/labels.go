@@ -0,0 +1,23 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// labelFilter reads repeated ?label=key=value query parameters. Results must
+// carry every requested label.
+func labelFilter(ctx *gin.Context) (Schemas.Labels, error) {
+	labels := Schemas.Labels{}
+	for _, pair := range ctx.QueryArray("label") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label filter %q: expected key=value", pair)
+		}
+		labels[key] = value
+	}
+	return labels, labels.Validate()
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+const (
+	defaultMetricRange = 24 * time.Hour
+	maxMetricRange     = 400 * 24 * time.Hour
+)
+
+// maxMetricRanges caps the range of a query per resolution, keeping a series
+// to a few thousand points.
+var maxMetricRanges = map[string]time.Duration{
+	Schemas.MetricResolutionMinute: 2 * 24 * time.Hour,
+	Schemas.MetricResolutionHour:   90 * 24 * time.Hour,
+	Schemas.MetricResolutionDay:    maxMetricRange,
+}
+
+// IngestMetrics records data points for a service. It does not go through
+// Clerk: an ingest key sent as a bearer token identifies the org.
+func (a *Api) IngestMetrics(ctx *gin.Context) {
+	token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Missing ingest key: send Authorization: Bearer <key>"})
+		return
+	}
+	orgID, err := dbrequests.UseIngestKey(ctx.Request.Context(), a.DB, token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unknown ingest key"})
+		} else {
+			log.Println("Error in checking ingest key:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record metrics"})
+		}
+		return
+	}
+
+	serviceID, ok := a.serviceParam(ctx, orgID)
+	if !ok {
+		return
+	}
+
+	var batch Schemas.MetricBatch
+	if err := ctx.ShouldBindJSON(&batch); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	now := time.Now()
+	if err := batch.Normalize(now); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	if err := dbrequests.RecordMetricPoints(ctx.Request.Context(), a.DB, serviceID, batch.Points, now); err != nil {
+		log.Println("Error in recording metrics:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record metrics"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Metrics recorded", "accepted": len(batch.Points)})
+}
+
+// GetServiceMetrics returns a metric series of a service, or the names of its
+// metrics when no metric is asked for. Without a resolution one is picked
+// from the length of the range.
+func (a *Api) GetServiceMetrics(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	serviceID, ok := a.serviceParam(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+
+	metric := ctx.Query("metric")
+	if metric == "" {
+		names, err := dbrequests.GetMetricNames(a.DB, serviceID)
+		if err != nil {
+			log.Println("Error in fetching metric names:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch metrics"})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"service_id": serviceID, "metrics": names})
+		return
+	}
+
+	from, to, ok := reportRange(ctx, defaultMetricRange, maxMetricRange)
+	if !ok {
+		return
+	}
+
+	resolution := ctx.Query("resolution")
+	switch {
+	case resolution == "":
+		resolution = metricResolutionFor(to.Sub(from))
+	case !slices.Contains(Schemas.MetricResolutions, resolution):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "resolution must be minute, hour or day"})
+		return
+	case to.Sub(from) > maxMetricRanges[resolution]:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Range is too long for %s resolution", resolution)})
+		return
+	}
+
+	points, err := dbrequests.GetMetricBuckets(a.DB, serviceID, metric, resolution, from, to)
+	if err != nil {
+		log.Println("Error in fetching metrics:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch metrics"})
+		return
+	}
+	if points == nil {
+		points = []Schemas.MetricBucket{}
+	}
+
+	ctx.JSON(http.StatusOK, Schemas.MetricSeries{
+		ServiceID:  serviceID,
+		Metric:     metric,
+		Resolution: resolution,
+		From:       from,
+		To:         to,
+		Points:     points,
+	})
+}
+
+// metricResolutionFor picks the finest resolution that keeps a range of the
+// given length to a few hundred points.
+func metricResolutionFor(span time.Duration) string {
+	switch {
+	case span <= 6*time.Hour:
+		return Schemas.MetricResolutionMinute
+	case span <= 14*24*time.Hour:
+		return Schemas.MetricResolutionHour
+	default:
+		return Schemas.MetricResolutionDay
+	}
+}
+
+func (a *Api) GetIngestKeys(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	keys, err := dbrequests.GetIngestKeys(a.DB, clerkUser.Org.ID)
+	if err != nil {
+		log.Println("Error in fetching ingest keys:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ingest keys"})
+		return
+	}
+	if keys == nil {
+		keys = []Schemas.IngestKey{}
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// CreateIngestKey creates an ingest key and returns its token. The token is
+// not stored and cannot be shown again.
+func (a *Api) CreateIngestKey(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	var request Schemas.IngestKeyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		log.Println("Error in generating ingest key:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ingest key"})
+		return
+	}
+
+	key, err := dbrequests.CreateIngestKey(a.DB, clerkUser.Org.ID, request.Name, token, clerkUser.ID)
+	if err != nil {
+		log.Println("Error in creating ingest key:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ingest key"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, key)
+}
+
+func (a *Api) DeleteIngestKey(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	keyID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ingest key id"})
+		return
+	}
+
+	if err := dbrequests.DeleteIngestKey(a.DB, keyID, clerkUser.Org.ID); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Ingest key not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete ingest key", "details": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Ingest key deleted successfully"})
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	"github.com/krnveersharma/Statuses/monitoring"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+const (
+	defaultMonitorResults = 100
+	maxMonitorResults     = 1000
+)
+
+// GetMonitors lists the org's monitors with their current state, narrowed to
+// one service with ?service_id=.
+func (a *Api) GetMonitors(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	serviceID := 0
+	if raw := ctx.Query("service_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+			return
+		}
+		serviceID = id
+	}
+
+	monitors, err := dbrequests.GetMonitorsForOrg(a.DB, clerkUser.Org.ID, serviceID)
+	if err != nil {
+		log.Println("Error in fetching monitors:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch monitors"})
+		return
+	}
+	if monitors == nil {
+		monitors = []Schemas.Monitor{}
+	}
+
+	ctx.JSON(http.StatusOK, monitors)
+}
+
+// GetMonitorResults returns a monitor's latest check results, newest first.
+func (a *Api) GetMonitorResults(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	monitorID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid monitor id"})
+		return
+	}
+	limit := defaultMonitorResults
+	if raw := ctx.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxMonitorResults {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+	}
+
+	if _, err := dbrequests.GetMonitor(a.DB, monitorID, clerkUser.Org.ID); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Monitor not found"})
+		} else {
+			log.Println("Error in fetching monitor:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch monitor results"})
+		}
+		return
+	}
+
+	results, err := dbrequests.GetMonitorResults(a.DB, monitorID, limit)
+	if err != nil {
+		log.Println("Error in fetching monitor results:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch monitor results"})
+		return
+	}
+	if results == nil {
+		results = []Schemas.MonitorResult{}
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
+
+func (a *Api) CreateMonitor(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	request, ok := a.bindMonitorRequest(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+
+	monitor, err := dbrequests.CreateMonitor(a.DB, request)
+	if err != nil {
+		log.Println("Error in creating monitor:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create monitor"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, monitor)
+}
+
+// EditMonitor replaces a monitor's configuration; its failure streak is kept.
+func (a *Api) EditMonitor(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	monitorID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid monitor id"})
+		return
+	}
+	request, ok := a.bindMonitorRequest(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+
+	monitor, released, err := dbrequests.UpdateMonitor(a.DB, monitorID, clerkUser.Org.ID, request)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Monitor not found"})
+		} else {
+			log.Println("Error in updating monitor:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update monitor"})
+		}
+		return
+	}
+	if released != nil {
+		reason := "was disabled"
+		if monitor.ServiceID != released.ServiceID {
+			reason = "was moved to another service"
+		} else if monitor.FailureStatus != released.FailureStatus {
+			reason = "had its failure status changed"
+		}
+		monitoring.MonitorReleased(context.Background(), a.DB, *released, reason)
+	}
+
+	ctx.JSON(http.StatusOK, monitor)
+}
+
+func (a *Api) DeleteMonitor(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	monitorID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid monitor id"})
+		return
+	}
+
+	monitor, err := dbrequests.DeleteMonitor(a.DB, monitorID, clerkUser.Org.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Monitor not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete monitor", "details": err.Error()})
+		}
+		return
+	}
+	if monitor.Tripped {
+		monitoring.MonitorReleased(context.Background(), a.DB, monitor, "was deleted")
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Monitor deleted successfully"})
+}
+
+// bindMonitorRequest binds and normalizes a monitor request and checks that
+// its service belongs to the org, writing an error response and returning
+// false otherwise.
+func (a *Api) bindMonitorRequest(ctx *gin.Context, orgID string) (Schemas.MonitorRequest, bool) {
+	var request Schemas.MonitorRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		respondBindError(ctx, err)
+		return request, false
+	}
+	if err := request.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return request, false
+	}
+	serviceID, ok := a.serviceRef(ctx, orgID, request.Service)
+	request.ServiceID = serviceID
+	return request, ok
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindMergePatch decodes a JSON Merge Patch (RFC 7396) body into dst. Members
+// outside allowed are rejected. The raw members are returned so callers can
+// tell an explicit null (remove) apart from an absent member (leave as is).
+func bindMergePatch(ctx *gin.Context, dst any, allowed ...string) (map[string]json.RawMessage, bool) {
+	contentType := ctx.ContentType()
+	if contentType != "application/merge-patch+json" && contentType != "application/json" {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be application/merge-patch+json"})
+		return nil, false
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return nil, false
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "patch must be a JSON object"})
+		return nil, false
+	}
+	for key := range members {
+		if !slices.Contains(allowed, key) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "unknown field: " + key})
+			return nil, false
+		}
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil {
+		respondBindError(ctx, err)
+		return nil, false
+	}
+	return members, true
+}
+
+// nullMembers returns which of the given members were explicitly set to null.
+func nullMembers(members map[string]json.RawMessage, keys ...string) []string {
+	var nulls []string
+	for _, key := range keys {
+		if raw, ok := members[key]; ok && bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			nulls = append(nulls, key)
+		}
+	}
+	return nulls
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// publicCacheTTL is how long public responses are reused by the server
+	// and by browsers and CDNs.
+	publicCacheTTL = 30 * time.Second
+	// publicCacheControl lets caches serve a stale copy while they refetch.
+	publicCacheControl = "public, max-age=30, stale-while-revalidate=60"
+	// maxPublicCacheEntries bounds the cache; the oldest entries make room.
+	maxPublicCacheEntries = 10000
+)
+
+// publicQueryParams are the query parameters public responses depend on.
+// Others are left out of the cache key, so they cannot be used to bypass it.
+var publicQueryParams = []string{"service", "style", "label"}
+
+// publicCache keeps rendered public responses for a short time so the
+// unauthenticated endpoints do not hit the database on every request. Every
+// entry lives for the same TTL, so order tracks insertion and expiry alike.
+type publicCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type publicCacheEntry struct {
+	key         string
+	contentType string
+	body        []byte
+	etag        string
+	expires     time.Time
+}
+
+func newPublicCache() *publicCache {
+	return &publicCache{entries: map[string]*list.Element{}, order: list.New()}
+}
+
+func (c *publicCache) get(key string, now time.Time) (publicCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return publicCacheEntry{}, false
+	}
+	entry := element.Value.(publicCacheEntry)
+	if now.After(entry.expires) {
+		return publicCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *publicCache) put(entry publicCacheEntry, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[entry.key]; ok {
+		c.order.Remove(element)
+	}
+	c.entries[entry.key] = c.order.PushBack(entry)
+
+	// Expired entries sit at the front, so this stops at the first live one.
+	for front := c.order.Front(); front != nil; front = c.order.Front() {
+		oldest := front.Value.(publicCacheEntry)
+		if !now.After(oldest.expires) && c.order.Len() <= maxPublicCacheEntries {
+			break
+		}
+		c.order.Remove(front)
+		delete(c.entries, oldest.key)
+	}
+}
+
+// publicCacheKey identifies a public response by its path and the query
+// parameters it depends on, in a fixed order.
+func publicCacheKey(ctx *gin.Context) string {
+	query := url.Values{}
+	for _, name := range publicQueryParams {
+		if value, ok := ctx.GetQuery(name); ok {
+			query.Set(name, value)
+		}
+	}
+	return ctx.Request.URL.Path + "?" + query.Encode()
+}
+
+// servePublic answers from the cache when it can and otherwise renders the
+// response with build and caches it. Responses carry an ETag, and a matching
+// If-None-Match gets 304. build writes its own error response and returns
+// false when it fails; failures are not cached.
+func (a *Api) servePublic(ctx *gin.Context, contentType string, build func() ([]byte, bool)) {
+	key := publicCacheKey(ctx)
+	now := time.Now()
+
+	entry, ok := a.Public.get(key, now)
+	if !ok {
+		body, ok := build()
+		if !ok {
+			return
+		}
+		sum := sha256.Sum256(body)
+		entry = publicCacheEntry{
+			key:         key,
+			contentType: contentType,
+			body:        body,
+			etag:        fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:8])),
+			expires:     now.Add(publicCacheTTL),
+		}
+		a.Public.put(entry, now)
+	}
+
+	ctx.Header("Cache-Control", publicCacheControl)
+	ctx.Header("ETag", entry.etag)
+	if ctx.GetHeader("If-None-Match") == entry.etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, entry.contentType, entry.body)
+}
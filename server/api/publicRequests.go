@@ -0,0 +1,200 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// rememberOrganization records the org slug from the Clerk claims so public
+// pages can be found by slug without Clerk. Slugs already synced by this
+// process are skipped.
+func (a *Api) rememberOrganization(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+	if clerkUser.Org == nil || clerkUser.Org.ID == "" || clerkUser.Org.Slug == "" {
+		return
+	}
+
+	if slug, ok := a.orgSlugs.Load(clerkUser.Org.ID); ok && slug == clerkUser.Org.Slug {
+		return
+	}
+	if err := dbrequests.SyncOrganization(a.DB, clerkUser.Org.ID, clerkUser.Org.Slug); err != nil {
+		log.Println("Error in syncing organization:", err)
+		return
+	}
+	a.orgSlugs.Store(clerkUser.Org.ID, clerkUser.Org.Slug)
+}
+
+func (a *Api) GetStatusPage(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	settings, err := dbrequests.GetStatusPageSettings(a.DB, clerkUser.Org.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Organization has no slug"})
+		} else {
+			log.Println("Error in fetching status page settings:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch status page"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// SetStatusPage turns the org's public status page on or off.
+func (a *Api) SetStatusPage(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	var request Schemas.StatusPageRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	settings, err := dbrequests.SetStatusPageEnabled(a.DB, clerkUser.Org.ID, *request.Enabled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Organization has no slug"})
+		} else {
+			log.Println("Error in updating status page settings:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status page"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// GetPublicSummary serves an org's public status page data. It needs no
+// authentication.
+func (a *Api) GetPublicSummary(ctx *gin.Context) {
+	a.servePublic(ctx, "application/json; charset=utf-8", func() ([]byte, bool) {
+		orgID, ok := a.publicOrg(ctx)
+		if !ok {
+			return nil, false
+		}
+
+		summary, err := a.loadStatusPageSummary(orgID, ctx.Param("orgSlug"))
+		if err != nil {
+			log.Println("Error in loading status page:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load status page"})
+			return nil, false
+		}
+		return marshalPublic(ctx, summary)
+	})
+}
+
+// publicOrg resolves the :orgSlug parameter, answering 404 when no enabled
+// status page is served under it.
+func (a *Api) publicOrg(ctx *gin.Context) (string, bool) {
+	orgID, err := dbrequests.PublicOrgID(a.DB, ctx.Param("orgSlug"))
+	if err == sql.ErrNoRows {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Status page not found"})
+		return "", false
+	}
+	if err != nil {
+		log.Println("Error in resolving status page:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load status page"})
+		return "", false
+	}
+	return orgID, true
+}
+
+// loadStatusPageSummary gathers the visible services and groups, unresolved
+// incidents and upcoming maintenance of an org. Maintenance counts as
+// upcoming until its start time and is listed soonest first.
+func (a *Api) loadStatusPageSummary(orgID, slug string) (Schemas.StatusPageSummary, error) {
+	now := time.Now().UTC()
+	summary := Schemas.StatusPageSummary{
+		Page:                Schemas.StatusPageSettings{Slug: slug, Enabled: true},
+		Status:              Schemas.ServiceOperational,
+		Groups:              []Schemas.PublicServiceGroup{},
+		Incidents:           []Schemas.PublicIncident{},
+		UpcomingMaintenance: []Schemas.PublicIncident{},
+		GeneratedAt:         now,
+	}
+
+	services, err := dbrequests.GetPublicServices(a.DB, orgID)
+	if err != nil {
+		return summary, err
+	}
+	summary.Services = services
+
+	groupStatus := map[int]Schemas.ServiceStatus{}
+	for _, service := range services {
+		if service.Status.Worse(summary.Status) {
+			summary.Status = service.Status
+		}
+		if service.GroupID != nil {
+			current, ok := groupStatus[*service.GroupID]
+			if !ok || service.Status.Worse(current) {
+				groupStatus[*service.GroupID] = service.Status
+			}
+		}
+	}
+
+	groups, err := dbrequests.GetServiceGroups(a.DB, orgID)
+	if err != nil {
+		return summary, err
+	}
+	for _, group := range groups {
+		status, ok := groupStatus[group.ID]
+		if !ok {
+			continue
+		}
+		summary.Groups = append(summary.Groups, Schemas.PublicServiceGroup{
+			ID: group.ID, Name: group.Name, Position: group.Position, Status: status,
+		})
+	}
+
+	incidents, err := dbrequests.GetPublicIncidents(a.DB, orgID)
+	if err != nil {
+		return summary, err
+	}
+	for _, incident := range incidents {
+		if incident.Status == Schemas.IncidentMaintenance && incident.StartedAt.After(now) {
+			summary.UpcomingMaintenance = append(summary.UpcomingMaintenance, incident)
+		} else {
+			summary.Incidents = append(summary.Incidents, incident)
+		}
+	}
+	slices.SortFunc(summary.UpcomingMaintenance, func(x, y Schemas.PublicIncident) int {
+		return x.StartedAt.Compare(y.StartedAt)
+	})
+
+	return summary, nil
+}
+
+// marshalPublic encodes a public response body, answering 500 on failure.
+func marshalPublic(ctx *gin.Context, v any) ([]byte, bool) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Println("Error marshaling JSON:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load status page"})
+		return nil, false
+	}
+	return body, true
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/krnveersharma/Statuses/uptime"
+)
+
+const (
+	defaultReportRange = 90 * 24 * time.Hour
+	maxReportRange     = 2 * 366 * 24 * time.Hour
+	maxUptimeRange     = 366 * 24 * time.Hour
+)
+
+// GetIncidentMetrics reports MTTA, MTTM and MTTR for incidents that started in
+// [from, to), overall and by severity, service and month. from/to accept
+// YYYY-MM-DD or RFC 3339 and default to the last 90 days.
+func (a *Api) GetIncidentMetrics(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	from, to, ok := reportRange(ctx, defaultReportRange, maxReportRange)
+	if !ok {
+		return
+	}
+
+	timings, err := dbrequests.GetIncidentTimingsForOrg(a.DB, clerkUser.Org.ID, from, to)
+	if err != nil {
+		log.Println("Error in fetching incident timings:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build report"})
+		return
+	}
+
+	services, err := dbrequests.GetServicesForOrg(a.DB, clerkUser.Org.ID, nil)
+	if err != nil {
+		log.Println("Error in fetching services:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build report"})
+		return
+	}
+	serviceNames := map[int]string{}
+	for _, service := range services {
+		serviceNames[service.ID] = service.Name
+	}
+
+	var overall metricsAccumulator
+	bySeverity := map[string]*metricsAccumulator{}
+	byService := map[int]*metricsAccumulator{}
+	byMonth := map[string]*metricsAccumulator{}
+	for _, t := range timings {
+		overall.add(t)
+
+		severity := t.Severity
+		if severity == "" {
+			severity = "unspecified"
+		}
+		accumulatorFor(bySeverity, severity).add(t)
+		accumulatorFor(byMonth, t.StartedAt.UTC().Format("2006-01")).add(t)
+		for _, serviceID := range t.ServiceIDs {
+			accumulatorFor(byService, int(serviceID)).add(t)
+		}
+	}
+
+	report := Schemas.IncidentMetricsReport{
+		From:       from,
+		To:         to,
+		Overall:    overall.result(),
+		BySeverity: []Schemas.SeverityIncidentMetrics{},
+		ByService:  []Schemas.ServiceIncidentMetrics{},
+		ByMonth:    []Schemas.MonthIncidentMetrics{},
+	}
+	for _, severity := range append(slices.Clone(Schemas.IncidentSeverities), "unspecified") {
+		if acc, ok := bySeverity[severity]; ok {
+			report.BySeverity = append(report.BySeverity, Schemas.SeverityIncidentMetrics{Severity: severity, IncidentMetrics: acc.result()})
+		}
+	}
+	for serviceID, acc := range byService {
+		report.ByService = append(report.ByService, Schemas.ServiceIncidentMetrics{
+			ServiceID:       serviceID,
+			ServiceName:     serviceNames[serviceID],
+			IncidentMetrics: acc.result(),
+		})
+	}
+	slices.SortFunc(report.ByService, func(x, y Schemas.ServiceIncidentMetrics) int {
+		return strings.Compare(x.ServiceName, y.ServiceName)
+	})
+	for month, acc := range byMonth {
+		report.ByMonth = append(report.ByMonth, Schemas.MonthIncidentMetrics{Month: month, IncidentMetrics: acc.result()})
+	}
+	slices.SortFunc(report.ByMonth, func(x, y Schemas.MonthIncidentMetrics) int {
+		return strings.Compare(x.Month, y.Month)
+	})
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// GetServiceUptime reports, for each service, the time spent in each status,
+// the uptime percentage and one bar per UTC day over [from, to). Ranges default
+// to the last 90 days; services can be narrowed with ?label=key=value.
+func (a *Api) GetServiceUptime(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	from, to, ok := reportRange(ctx, defaultReportRange, maxUptimeRange)
+	if !ok {
+		return
+	}
+	labels, err := labelFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	services, err := dbrequests.GetServicesForOrg(a.DB, clerkUser.Org.ID, labels)
+	if err != nil {
+		log.Println("Error in fetching services:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build report"})
+		return
+	}
+	history, err := dbrequests.GetStatusHistoryForOrg(a.DB, clerkUser.Org.ID, from, to)
+	if err != nil {
+		log.Println("Error in fetching status history:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build report"})
+		return
+	}
+
+	now := time.Now()
+	report := Schemas.UptimeReport{From: from, To: to, Services: []Schemas.ServiceUptime{}}
+	for _, service := range services {
+		report.Services = append(report.Services, Schemas.ServiceUptime{
+			ServiceID:   service.ID,
+			ServiceName: service.Name,
+			StatusTally: uptime.Tally(history[service.ID], from, to, now),
+			Days:        uptime.Days(history[service.ID], from, to, now),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// reportRange parses the from/to query parameters, writing a 400 response
+// and returning false when they are invalid.
+func reportRange(ctx *gin.Context, defaultRange, maxRange time.Duration) (time.Time, time.Time, bool) {
+	to := time.Now().UTC()
+	if raw := ctx.Query("to"); raw != "" {
+		parsed, err := parseReportTime(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to: use YYYY-MM-DD or RFC 3339"})
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultRange)
+	if raw := ctx.Query("from"); raw != "" {
+		parsed, err := parseReportTime(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from: use YYYY-MM-DD or RFC 3339"})
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Sub(from) > maxRange {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Range is too long"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+func parseReportTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+type metricsAccumulator struct {
+	metrics                Schemas.IncidentMetrics
+	sumTTA, sumTTM, sumTTR float64
+}
+
+func accumulatorFor[K comparable](groups map[K]*metricsAccumulator, key K) *metricsAccumulator {
+	acc, ok := groups[key]
+	if !ok {
+		acc = &metricsAccumulator{}
+		groups[key] = acc
+	}
+	return acc
+}
+
+func (acc *metricsAccumulator) add(t Schemas.IncidentTimings) {
+	acc.metrics.Incidents++
+	if t.TimeToAcknowledgeSeconds != nil {
+		acc.metrics.Acknowledged++
+		acc.sumTTA += *t.TimeToAcknowledgeSeconds
+	}
+	if t.TimeToMitigateSeconds != nil {
+		acc.metrics.Mitigated++
+		acc.sumTTM += *t.TimeToMitigateSeconds
+	}
+	if t.TimeToResolveSeconds != nil {
+		acc.metrics.Resolved++
+		acc.sumTTR += *t.TimeToResolveSeconds
+	}
+}
+
+func (acc *metricsAccumulator) result() Schemas.IncidentMetrics {
+	metrics := acc.metrics
+	metrics.MTTASeconds = mean(acc.sumTTA, metrics.Acknowledged)
+	metrics.MTTMSeconds = mean(acc.sumTTM, metrics.Mitigated)
+	metrics.MTTRSeconds = mean(acc.sumTTR, metrics.Resolved)
+	return metrics
+}
+
+func mean(sum float64, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+	m := sum / float64(count)
+	return &m
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// GetServiceGraph returns the org's services and the dependencies between
+// them.
+func (a *Api) GetServiceGraph(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	services, err := dbrequests.GetServicesForOrg(a.DB, clerkUser.Org.ID, nil)
+	if err != nil {
+		log.Println("Error in fetching services:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service graph"})
+		return
+	}
+	dependencies, err := dbrequests.GetServiceDependencies(a.DB, clerkUser.Org.ID)
+	if err != nil {
+		log.Println("Error in fetching service dependencies:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service graph"})
+		return
+	}
+
+	graph := Schemas.ServiceGraph{Services: services, Dependencies: dependencies}
+	if graph.Services == nil {
+		graph.Services = []Schemas.Service{}
+	}
+	if graph.Dependencies == nil {
+		graph.Dependencies = []Schemas.ServiceDependency{}
+	}
+	ctx.JSON(http.StatusOK, graph)
+}
+
+// SetServiceDependencies replaces the services a service depends on. Changes
+// that would make the graph cyclic are rejected.
+func (a *Api) SetServiceDependencies(ctx *gin.Context) {
+	var request Schemas.ServiceDependenciesRequest
+
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	serviceID, ok := a.serviceParam(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	services, err := dbrequests.GetServicesForOrg(a.DB, clerkUser.Org.ID, nil)
+	if err != nil {
+		log.Println("Error in fetching services:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update dependencies"})
+		return
+	}
+	names := map[int]string{}
+	for _, service := range services {
+		names[service.ID] = service.Name
+	}
+	if _, ok := names[serviceID]; !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+	for _, dependsOnID := range request.DependsOn {
+		if dependsOnID == serviceID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "a service cannot depend on itself"})
+			return
+		}
+		if _, ok := names[dependsOnID]; !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": fmt.Sprintf("unknown service %d", dependsOnID)})
+			return
+		}
+	}
+
+	err = dbrequests.SetServiceDependencies(a.DB, clerkUser.Org.ID, serviceID, request.DependsOn, func(current []Schemas.ServiceDependency) error {
+		graph := map[int][]int{}
+		for _, d := range current {
+			if d.ServiceID != serviceID {
+				graph[d.ServiceID] = append(graph[d.ServiceID], d.DependsOnID)
+			}
+		}
+		for _, dependsOnID := range request.DependsOn {
+			if path := dependencyPath(graph, dependsOnID, serviceID); path != nil {
+				return dependencyCycleError{path: append([]int{serviceID}, path...)}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		var cycle dependencyCycleError
+		if errors.As(err, &cycle) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": cycle.describe(names)})
+			return
+		}
+		log.Println("Error in saving service dependencies:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update dependencies"})
+		return
+	}
+
+	dependsOn := request.DependsOn
+	if dependsOn == nil {
+		dependsOn = []int{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"service_id": serviceID, "depends_on": dependsOn})
+}
+
+// GetIncidentBlastRadius returns the services an incident affects directly
+// and those that may be impacted through their dependencies.
+func (a *Api) GetIncidentBlastRadius(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	incidentID := ctx.Param("id")
+	if _, err := dbrequests.GetIncidentByID(a.DB, incidentID, clerkUser.Org.ID); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+		} else {
+			log.Println("error in fetching incident:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute blast radius"})
+		}
+		return
+	}
+
+	affected, err := dbrequests.GetServicesAffected(a.DB, incidentID)
+	if err != nil {
+		log.Println("error in fetching linked services:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute blast radius"})
+		return
+	}
+	impacted, err := a.potentiallyImpacted(clerkUser.Org.ID, affected)
+	if err != nil {
+		log.Println("error in computing blast radius:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute blast radius"})
+		return
+	}
+
+	if affected == nil {
+		affected = []Schemas.Service{}
+	}
+	ctx.JSON(http.StatusOK, Schemas.BlastRadius{IncidentID: incidentID, Affected: affected, PotentiallyImpacted: impacted})
+}
+
+// potentiallyImpacted walks the dependency graph upwards from the affected
+// services and returns every service that depends on one of them, nearest
+// first, leaving out services that are affected themselves.
+func (a *Api) potentiallyImpacted(orgID string, affected []Schemas.Service) ([]Schemas.ImpactedService, error) {
+	impacted := []Schemas.ImpactedService{}
+	if len(affected) == 0 {
+		return impacted, nil
+	}
+
+	dependencies, err := dbrequests.GetServiceDependencies(a.DB, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(dependencies) == 0 {
+		return impacted, nil
+	}
+	services, err := dbrequests.GetServicesForOrg(a.DB, orgID, nil)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[int]Schemas.Service{}
+	for _, service := range services {
+		byID[service.ID] = service
+	}
+
+	dependents := map[int][]int{}
+	for _, d := range dependencies {
+		dependents[d.DependsOnID] = append(dependents[d.DependsOnID], d.ServiceID)
+	}
+
+	via := map[int][]int{}
+	var queue []int
+	for _, service := range affected {
+		via[service.ID] = nil
+		queue = append(queue, service.ID)
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[current] {
+			if _, seen := via[dependent]; seen {
+				continue
+			}
+			path := append(append([]int{}, via[current]...), current)
+			via[dependent] = path
+			queue = append(queue, dependent)
+			impacted = append(impacted, Schemas.ImpactedService{Service: byID[dependent], Via: path})
+		}
+	}
+
+	return impacted, nil
+}
+
+// dependencyPath returns a chain of service IDs from one service to another
+// following depends-on edges, or nil when to is unreachable.
+func dependencyPath(graph map[int][]int, from, to int) []int {
+	visited := map[int]bool{}
+	var walk func(id int) []int
+	walk = func(id int) []int {
+		if id == to {
+			return []int{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+		for _, next := range graph[id] {
+			if path := walk(next); path != nil {
+				return append([]int{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
+
+type dependencyCycleError struct {
+	path []int
+}
+
+func (e dependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency cycle through services %v", e.path)
+}
+
+func (e dependencyCycleError) describe(names map[int]string) string {
+	steps := make([]string, len(e.path))
+	for i, id := range e.path {
+		steps[i] = names[id]
+	}
+	return "dependencies would form a cycle: " + strings.Join(steps, " -> ")
+}
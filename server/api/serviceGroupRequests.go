@@ -0,0 +1,182 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/krnveersharma/Statuses/websocketsHandler"
+)
+
+// GetServiceGroups returns the org's groups in display order, each with its
+// services and aggregate status.
+func (a *Api) GetServiceGroups(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	tree, err := a.loadServiceTree(clerkUser.Org.ID, nil)
+	if err != nil {
+		log.Println("Error in fetching service groups:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service groups"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tree.Groups)
+}
+
+func (a *Api) CreateServiceGroup(ctx *gin.Context) {
+	var request Schemas.ServiceGroupRequest
+
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	group, err := dbrequests.CreateServiceGroup(a.DB, clerkUser.Org.ID, request)
+	if err != nil {
+		log.Println("error in creating service group:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create service group"})
+		return
+	}
+	group.Status = Schemas.AggregateStatus(nil)
+	group.Services = []Schemas.Service{}
+
+	ctx.JSON(http.StatusCreated, group)
+
+	websocketsHandler.CreateServiceGroup(clerkUser.Org.ID, group)
+}
+
+// EditServiceGroup renames or reorders a group.
+func (a *Api) EditServiceGroup(ctx *gin.Context) {
+	var request Schemas.ServiceGroupRequest
+
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	groupID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group id"})
+		return
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	group, err := dbrequests.UpdateServiceGroup(a.DB, groupID, clerkUser.Org.ID, request)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Service group not found"})
+		} else {
+			log.Println("error in updating service group:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service group"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+
+	websocketsHandler.UpdateServiceGroup(strconv.Itoa(groupID), clerkUser.Org.ID, group)
+}
+
+// DeleteServiceGroup removes a group; its services stay and become ungrouped.
+func (a *Api) DeleteServiceGroup(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	groupID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group id"})
+		return
+	}
+
+	if err := dbrequests.DeleteServiceGroup(a.DB, groupID, clerkUser.Org.ID); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Service group not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service group", "details": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Service group deleted successfully"})
+
+	websocketsHandler.DeleteServiceGroup(strconv.Itoa(groupID), clerkUser.Org.ID)
+}
+
+// loadServiceTree nests the org's services, optionally narrowed by labels,
+// under their groups. Groups keep their display order even when empty.
+func (a *Api) loadServiceTree(orgID string, labels Schemas.Labels) (Schemas.ServiceTree, error) {
+	tree := Schemas.ServiceTree{Groups: []Schemas.ServiceGroup{}, Ungrouped: []Schemas.Service{}}
+
+	groups, err := dbrequests.GetServiceGroups(a.DB, orgID)
+	if err != nil {
+		return tree, err
+	}
+	services, err := dbrequests.GetServicesForOrg(a.DB, orgID, labels)
+	if err != nil {
+		return tree, err
+	}
+
+	byGroup := map[int][]Schemas.Service{}
+	for _, service := range services {
+		if service.GroupID == nil {
+			tree.Ungrouped = append(tree.Ungrouped, service)
+			continue
+		}
+		byGroup[*service.GroupID] = append(byGroup[*service.GroupID], service)
+	}
+	for _, group := range groups {
+		group.Services = byGroup[group.ID]
+		if group.Services == nil {
+			group.Services = []Schemas.Service{}
+		}
+		group.Status = Schemas.AggregateStatus(group.Services)
+		tree.Groups = append(tree.Groups, group)
+	}
+
+	return tree, nil
+}
+
+// validServiceGroup checks that groupID, when set, names one of the org's
+// groups, writing a 400 response and returning false otherwise.
+func (a *Api) validServiceGroup(ctx *gin.Context, groupID *int, orgID string) bool {
+	if groupID == nil || *groupID == 0 {
+		return true
+	}
+
+	exists, err := dbrequests.ServiceGroupExists(a.DB, *groupID, orgID)
+	if err != nil {
+		log.Println("error in checking service group:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check service group"})
+		return false
+	}
+	if !exists {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "unknown group_id"})
+		return false
+	}
+	return true
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,6 +15,11 @@ import (
 	"github.com/krnveersharma/Statuses/websocketsHandler"
 )
 
+const (
+	invalidStatusModeMessage = "status_mode must be manual or auto"
+	invalidSlugMessage       = "slug must be up to 63 lowercase letters, digits and single hyphens, with at least one letter"
+)
+
 func (a *Api) CreateService(ctx *gin.Context) {
 	clerkUserRaw, exists := ctx.Get("user")
 	var ServiceRequest Schemas.ServiceRequest
@@ -25,14 +31,45 @@ func (a *Api) CreateService(ctx *gin.Context) {
 	clerkUser := clerkUserRaw.(*middlewares.UserData)
 
 	if err := ctx.ShouldBindBodyWithJSON(&ServiceRequest); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request"})
+		respondBindError(ctx, err)
+		return
+	}
+	if !requireServiceStatus(ctx, ServiceRequest.Status) {
+		return
+	}
+
+	if err := ServiceRequest.Labels.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request", "details": err.Error()})
+		return
+	}
+	if err := ServiceRequest.ServiceMetadata.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request", "details": err.Error()})
+		return
+	}
+	if !Schemas.ValidStatusMode(ServiceRequest.StatusMode) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidStatusModeMessage})
+		return
+	}
+	if ServiceRequest.Slug != "" && !Schemas.ValidSlug(ServiceRequest.Slug) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidSlugMessage})
+		return
+	}
+	if ServiceRequest.Slug == "" {
+		ServiceRequest.Slug = Schemas.Slugify(ServiceRequest.Name)
+	}
+	if !a.validServiceGroup(ctx, ServiceRequest.GroupID, clerkUser.Org.ID) {
 		return
 	}
 
 	service, err := dbrequests.AddService(a.DB, ServiceRequest, clerkUser.Org.ID, clerkUser.ID)
 
+	if errors.Is(err, dbrequests.ErrSlugTaken) {
+		a.respondSlugTaken(ctx, clerkUser.Org.ID, ServiceRequest.Slug)
+		return
+	}
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		log.Println("Error in adding service:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add service"})
 		return
 	}
 
@@ -40,6 +77,7 @@ func (a *Api) CreateService(ctx *gin.Context) {
 
 	// Broadcast to websockets
 	websocketsHandler.CreateService(clerkUser.Org.ID, service)
+	a.refreshAutoStatuses(clerkUser.Org.ID)
 }
 
 func (a *Api) GetServices(ctx *gin.Context) {
@@ -52,26 +90,31 @@ func (a *Api) GetServices(ctx *gin.Context) {
 	clerkUser := clerkUserRaw.(*middlewares.UserData)
 
 	fmt.Printf("user is: %+v", clerkUser)
-	rows, err := a.DB.Query("SELECT id, name, status FROM services where clerk_org_id = $1", clerkUser.Org.ID)
-
+	labels, err := labelFilter(ctx)
 	if err != nil {
-		log.Println("Error in fetching services:", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request", "details": err.Error()})
 		return
 	}
-	defer rows.Close()
 
-	var services []Schemas.Service
-	for rows.Next() {
-		var s Schemas.Service
-		if err := rows.Scan(&s.ID, &s.Name, &s.Status); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan service row"})
+	if ctx.Query("view") == "flat" {
+		services, err := dbrequests.GetServicesForOrg(a.DB, clerkUser.Org.ID, labels)
+		if err != nil {
+			log.Println("Error in fetching services:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
 			return
 		}
-		services = append(services, s)
+		ctx.JSON(http.StatusOK, services)
+		return
+	}
+
+	tree, err := a.loadServiceTree(clerkUser.Org.ID, labels)
+	if err != nil {
+		log.Println("Error in fetching services:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
 	}
 
-	ctx.JSON(http.StatusOK, services)
+	ctx.JSON(http.StatusOK, tree)
 }
 
 func (a *Api) GetServiceByID(ctx *gin.Context) {
@@ -82,13 +125,12 @@ func (a *Api) GetServiceByID(ctx *gin.Context) {
 	}
 	clerkUser := clerkUserRaw.(*middlewares.UserData)
 
-	serviceId := ctx.Param("id")
-	if serviceId == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing Service ID"})
+	serviceId, ok := a.serviceParam(ctx, clerkUser.Org.ID)
+	if !ok {
 		return
 	}
 
-	service, err := dbrequests.GetServiceByID(a.DB, serviceId, clerkUser.Org.ID)
+	service, err := dbrequests.GetServiceByID(a.DB, strconv.Itoa(serviceId), clerkUser.Org.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
@@ -99,12 +141,17 @@ func (a *Api) GetServiceByID(ctx *gin.Context) {
 		return
 	}
 
+	setETag(ctx, service.Version)
 	ctx.JSON(http.StatusOK, service)
 }
 
 func (a *Api) EditService(ctx *gin.Context) {
 	clerkUserRaw, exists := ctx.Get("user")
-	var service Schemas.Service
+	var request struct {
+		Schemas.Service
+		// ID names the service to edit by ID or slug.
+		ID Schemas.ServiceRef `json:"id"`
+	}
 
 	log.Println("[EditService] Called")
 
@@ -122,25 +169,184 @@ func (a *Api) EditService(ctx *gin.Context) {
 		return
 	}
 
-	if err := ctx.ShouldBindJSON(&service); err != nil {
+	if err := ctx.ShouldBindJSON(&request); err != nil {
 		log.Printf("[EditService] Failed to bind JSON: %v\n", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request"})
+		respondBindError(ctx, err)
+		return
+	}
+	service := request.Service
+	serviceID, err := dbrequests.ResolveServiceRef(a.DB, clerkUser.Org.ID, string(request.ID))
+	if err == sql.ErrNoRows {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("[EditService] Failed to resolve service: %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service"})
+		return
+	}
+	service.ID = serviceID
+	if !requireServiceStatus(ctx, service.Status) {
 		return
 	}
 	log.Printf("[EditService] Parsed service payload: %+v\n", service)
+	if err := service.Labels.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request", "details": err.Error()})
+		return
+	}
+	if err := service.ServiceMetadata.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request", "details": err.Error()})
+		return
+	}
+	if !Schemas.ValidStatusMode(service.StatusMode) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidStatusModeMessage})
+		return
+	}
+	if service.Slug != "" && !Schemas.ValidSlug(service.Slug) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidSlugMessage})
+		return
+	}
+	if !a.validServiceGroup(ctx, service.GroupID, clerkUser.Org.ID) {
+		return
+	}
+
+	version, ok, err := expectedVersion(ctx, service.Version)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": "Missing version: send an If-Match header or a version field"})
+		return
+	}
+
+	newVersion, err := dbrequests.EditService(a.DB, service, clerkUser.Org.ID, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, dbrequests.ErrVersionConflict):
+			a.respondServiceConflict(ctx, strconv.Itoa(service.ID), clerkUser.Org.ID)
+		case errors.Is(err, dbrequests.ErrSlugTaken):
+			a.respondSlugTaken(ctx, clerkUser.Org.ID, service.Slug)
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		default:
+			log.Printf("[EditService] Failed to update service: %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service"})
+		}
+		return
+	}
+	service.Version = newVersion
+
+	log.Printf("[EditService] Service %d updated successfully\n", service.ID)
+	setETag(ctx, newVersion)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Service Updated Successfully", "version": newVersion})
+
+	// Broadcast to websockets
+	websocketsHandler.UpdateService(strconv.Itoa(service.ID), clerkUser.Org.ID, service)
+	a.refreshAutoStatuses(clerkUser.Org.ID)
+}
+
+// respondServiceConflict answers a stale edit with 409 and the service as it
+// is currently stored.
+func (a *Api) respondServiceConflict(ctx *gin.Context, serviceID, orgID string) {
+	current, err := dbrequests.GetServiceByID(a.DB, serviceID, orgID)
+	if err != nil {
+		log.Printf("[EditService] Failed to load current service after conflict: %v\n", err)
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Service was modified by another request"})
+		return
+	}
+
+	setETag(ctx, current.Version)
+	ctx.JSON(http.StatusConflict, gin.H{"error": "Service was modified by another request", "current": current})
+}
+
+// PatchService applies a JSON Merge Patch to a service. If-Match (or a
+// version member) is optional; when given, stale patches are rejected with 409.
+func (a *Api) PatchService(ctx *gin.Context) {
+	var patch Schemas.ServicePatch
 
-	err := dbrequests.EditService(a.DB, service, clerkUser.Org.ID)
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	serviceId, ok := a.serviceParam(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+
+	allowed := append([]string{"name", "slug", "status", "status_mode", "group_id", "labels", "version"}, Schemas.MetadataFields...)
+	members, ok := bindMergePatch(ctx, &patch, allowed...)
+	if !ok {
+		return
+	}
+	if nulls := nullMembers(members, "name", "slug", "status", "status_mode", "labels"); len(nulls) > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": nulls[0] + " cannot be removed"})
+		return
+	}
+	if len(nullMembers(members, "group_id")) > 0 {
+		patch.GroupID = new(int)
+	}
+	patch.Clear(nullMembers(members, Schemas.MetadataFields...)...)
+	if err := patch.ServiceMetadata.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request", "details": err.Error()})
+		return
+	}
+	if !a.validServiceGroup(ctx, patch.GroupID, clerkUser.Org.ID) {
+		return
+	}
+	labels, _ := patch.Labels.Split()
+	if err := labels.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Request", "details": err.Error()})
+		return
+	}
+	if patch.Slug != nil && !Schemas.ValidSlug(*patch.Slug) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidSlugMessage})
+		return
+	}
+	if patch.StatusMode != nil && (*patch.StatusMode == "" || !Schemas.ValidStatusMode(*patch.StatusMode)) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": invalidStatusModeMessage})
+		return
+	}
+
+	var guard *int
+	bodyVersion := 0
+	if patch.Version != nil {
+		bodyVersion = *patch.Version
+	}
+	version, ok, err := expectedVersion(ctx, bodyVersion)
 	if err != nil {
-		log.Printf("[EditService] Failed to update service: %v\n", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
 		return
 	}
+	if ok {
+		guard = &version
+	}
 
-	log.Printf("[EditService] Service %s updated successfully\n", service.ID)
-	ctx.JSON(http.StatusOK, gin.H{"message": "Service Updated Successfully"})
+	service, err := dbrequests.PatchService(a.DB, serviceId, clerkUser.Org.ID, patch, guard)
+	if err != nil {
+		switch {
+		case errors.Is(err, dbrequests.ErrVersionConflict):
+			a.respondServiceConflict(ctx, strconv.Itoa(serviceId), clerkUser.Org.ID)
+		case errors.Is(err, dbrequests.ErrSlugTaken):
+			a.respondSlugTaken(ctx, clerkUser.Org.ID, *patch.Slug)
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		default:
+			log.Printf("[PatchService] Failed to update service: %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service"})
+		}
+		return
+	}
+
+	setETag(ctx, service.Version)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Service Updated Successfully", "service": service})
 
 	// Broadcast to websockets
 	websocketsHandler.UpdateService(strconv.Itoa(service.ID), clerkUser.Org.ID, service)
+	a.refreshAutoStatuses(clerkUser.Org.ID)
 }
 
 func (a *Api) DeleteService(ctx *gin.Context) {
@@ -151,18 +357,92 @@ func (a *Api) DeleteService(ctx *gin.Context) {
 	}
 	clerkUser := clerkUserRaw.(*middlewares.UserData)
 
-	serviceIdStr := ctx.Param("id")
-	serviceId, err := strconv.Atoi(serviceIdStr)
+	serviceId, ok := a.serviceParam(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+	force, err := strconv.ParseBool(ctx.DefaultQuery("force", "false"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": "force must be true or false"})
 		return
 	}
-	err = dbrequests.DeleteService(a.DB, serviceId, clerkUser.Org.ID)
+
+	open, notes, err := dbrequests.DeleteService(a.DB, serviceId, clerkUser.Org.ID, force, clerkUser.ID, fullName(*clerkUser))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service", "details": err.Error()})
+		switch {
+		case errors.Is(err, dbrequests.ErrServiceInUse):
+			ctx.JSON(http.StatusConflict, gin.H{
+				"error":     "Service is referenced by unresolved incidents",
+				"details":   "resolve or unlink these incidents first, or delete with ?force=true",
+				"incidents": open,
+			})
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		default:
+			log.Println("Error in deleting service:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service", "details": err.Error()})
+		}
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Service deleted successfully"})
 
 	websocketsHandler.DeleteService(strconv.Itoa(serviceId), clerkUser.Org.ID)
+	for _, note := range notes {
+		websocketsHandler.AddIncidentNote(note.IncidentId, clerkUser.Org.ID, note)
+	}
+}
+
+// serviceParam resolves the :id path parameter, which may be a service's ID
+// or its slug, answering 404 when the org has no such service.
+func (a *Api) serviceParam(ctx *gin.Context, orgID string) (int, bool) {
+	serviceID, err := dbrequests.ResolveServiceRef(a.DB, orgID, ctx.Param("id"))
+	if err == sql.ErrNoRows {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return 0, false
+	}
+	if err != nil {
+		log.Println("Error in resolving service:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service"})
+		return 0, false
+	}
+	return serviceID, true
+}
+
+// serviceRef resolves a service a request body gives by ID or slug,
+// answering 400 when the org has no such service.
+func (a *Api) serviceRef(ctx *gin.Context, orgID string, ref Schemas.ServiceRef) (int, bool) {
+	serviceID, err := dbrequests.ResolveServiceRef(a.DB, orgID, string(ref))
+	if err == sql.ErrNoRows {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": fmt.Sprintf("unknown service %q", ref)})
+		return 0, false
+	}
+	if err != nil {
+		log.Println("Error in resolving service:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check service"})
+		return 0, false
+	}
+	return serviceID, true
+}
+
+// respondSlugTaken answers a slug clash with 409 and the next free slug.
+func (a *Api) respondSlugTaken(ctx *gin.Context, orgID, slug string) {
+	body := gin.H{"error": "Slug already in use", "details": fmt.Sprintf("another service already uses the slug %q", slug)}
+	if suggestion, err := dbrequests.AvailableServiceSlug(a.DB, orgID, slug); err == nil {
+		body["suggestion"] = suggestion
+	}
+	ctx.JSON(http.StatusConflict, body)
+}
+
+// refreshAutoStatuses recomputes the org's auto-mode services after a change
+// to incidents or services and broadcasts those whose status moved.
+func (a *Api) refreshAutoStatuses(orgID string) {
+	changed, err := dbrequests.RecomputeAutoStatuses(a.DB, orgID)
+	if err != nil {
+		log.Println("Error in recomputing service statuses:", err)
+		return
+	}
+
+	for _, service := range changed {
+		websocketsHandler.UpdateService(strconv.Itoa(service.ID), orgID, service)
+	}
 }
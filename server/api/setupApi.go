@@ -1,22 +1,34 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/krnveersharma/Statuses/blobstore"
 	"github.com/krnveersharma/Statuses/config"
 	dbconnection "github.com/krnveersharma/Statuses/dbConnection"
+	"github.com/krnveersharma/Statuses/escalation"
+	"github.com/krnveersharma/Statuses/maintenance"
+	"github.com/krnveersharma/Statuses/metrics"
 	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	"github.com/krnveersharma/Statuses/monitoring"
+	"github.com/krnveersharma/Statuses/slo"
 	"github.com/krnveersharma/Statuses/websocketsHandler"
 )
 
 type Api struct {
 	Config config.Config
 	DB     *sql.DB
+	Blobs  blobstore.Store
+	Public *publicCache
+	// orgSlugs maps Clerk org IDs to the slug last synced by this process.
+	orgSlugs sync.Map
 }
 
 func SetupApi(config config.Config) error {
@@ -34,17 +46,53 @@ func SetupApi(config config.Config) error {
 		log.Fatalf("Failed to initialize Clerk service: %v", err)
 	}
 
+	blobs, err := blobstore.NewLocalStore(config.AttachmentsDir)
+	if err != nil {
+		return err
+	}
+
 	api := &Api{
 		Config: config,
 		DB:     db,
+		Blobs:  blobs,
+		Public: newPublicCache(),
 	}
 
+	// Background workers
+	go escalation.NewWorker(db, time.Minute).Run(context.Background())
+	go monitoring.NewScheduler(db, 5*time.Second).Run(context.Background())
+	go monitoring.NewHeartbeatChecker(db, 15*time.Second).Run(context.Background())
+	go slo.NewEvaluator(db, time.Minute).Run(context.Background())
+	go maintenance.NewWatcher(db, time.Minute).Run(context.Background())
+	go metrics.NewPruner(db, time.Hour).Run(context.Background())
+
 	server := gin.Default()
 
+	// Public status pages are read from any origin without credentials. gin
+	// fixes a route's middleware when it is registered, so registering these
+	// before server.Use keeps the stricter CORS policy below off them.
+	publicRoutes := server.Group("/public", cors.New(cors.Config{
+		AllowAllOrigins: true,
+		AllowMethods:    []string{"GET", "HEAD"},
+		AllowHeaders:    []string{"Origin", "If-None-Match"},
+		ExposeHeaders:   []string{"ETag"},
+		MaxAge:          12 * time.Hour,
+	}))
+	publicRoutes.GET("/:orgSlug/summary", api.GetPublicSummary)
+	publicRoutes.GET("/:orgSlug/api/v2/summary.json", api.GetStatuspageSummary)
+	publicRoutes.GET("/:orgSlug/api/v2/status.json", api.GetStatuspageStatus)
+	publicRoutes.GET("/:orgSlug/api/v2/components.json", api.GetStatuspageComponents)
+	publicRoutes.GET("/:orgSlug/api/v2/incidents/unresolved.json", api.GetStatuspageUnresolvedIncidents)
+	publicRoutes.GET("/:orgSlug/feed.rss", api.GetIncidentFeedRSS)
+	publicRoutes.GET("/:orgSlug/feed.atom", api.GetIncidentFeedAtom)
+	publicRoutes.GET("/:orgSlug/badge.svg", api.GetOrgBadge)
+	publicRoutes.GET("/:orgSlug/services/:slug/badge.svg", api.GetServiceBadge)
+
 	server.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{config.AllowedHost},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -53,20 +101,65 @@ func SetupApi(config config.Config) error {
 		c.JSON(200, gin.H{"message": "Statuses API is running!"})
 	})
 
-	userRoutes := server.Group("/user", middlewares.GetUserInfo(service, "user"))
+	// Unauthenticated: the token is the credential.
+	server.POST("/heartbeat/:token", api.ReceiveHeartbeat)
+	// Authenticated with an ingest key instead of Clerk.
+	server.POST("/ingest/services/:id/metrics", api.IngestMetrics)
+
+	userRoutes := server.Group("/user", middlewares.GetUserInfo(service, "user"), api.rememberOrganization)
 
 	userRoutes.GET("/", api.getUser)
+	userRoutes.GET("/meta/statuses", api.GetStatuses)
 	userRoutes.GET("/get-services", api.GetServices)
 	userRoutes.GET("/get-service/:id", api.GetServiceByID)
 	userRoutes.GET("/get-incidents", api.GetIncidents)
 	userRoutes.GET("/get-incident/:id", api.GetIncidentByID)
-
-	privateRoute := server.Group("/admin", middlewares.GetUserInfo(service, "admin"))
+	userRoutes.GET("/reports/incident-metrics", api.GetIncidentMetrics)
+	userRoutes.GET("/reports/uptime", api.GetServiceUptime)
+	userRoutes.GET("/escalation-policy", api.GetEscalationPolicy)
+	userRoutes.GET("/service-groups", api.GetServiceGroups)
+	userRoutes.GET("/service-dependencies", api.GetServiceGraph)
+	userRoutes.GET("/incident-blast-radius/:id", api.GetIncidentBlastRadius)
+	userRoutes.GET("/monitors", api.GetMonitors)
+	userRoutes.GET("/monitors/:id/results", api.GetMonitorResults)
+	userRoutes.GET("/heartbeats", api.GetHeartbeats)
+	userRoutes.GET("/service-metrics/:id", api.GetServiceMetrics)
+	userRoutes.GET("/slos", api.GetSLOs)
+	userRoutes.GET("/slos/:id", api.GetSLO)
+	userRoutes.GET("/status-page", api.GetStatusPage)
+	userRoutes.GET("/incident-attachments/:id/:attachmentId", api.DownloadAttachment)
+
+	privateRoute := server.Group("/admin", middlewares.GetUserInfo(service, "admin"), api.rememberOrganization)
 
 	privateRoute.POST("/create-service", api.CreateService)
 	privateRoute.POST("/create-incident", api.CreateIncident)
 	privateRoute.PUT("/edit-incident", api.EditIncident)
 	privateRoute.PUT("/edit-service", api.EditService)
+	privateRoute.PATCH("/edit-incident/:id", api.PatchIncident)
+	privateRoute.PATCH("/edit-service/:id", api.PatchService)
+	privateRoute.POST("/merge-incidents", api.MergeIncidents)
+	privateRoute.POST("/incident-notes/:id", api.AddIncidentNote)
+	privateRoute.PUT("/escalation-policy", api.SaveEscalationPolicy)
+	privateRoute.DELETE("/escalation-policy", api.DeleteEscalationPolicy)
+	privateRoute.POST("/service-groups", api.CreateServiceGroup)
+	privateRoute.PUT("/service-groups/:id", api.EditServiceGroup)
+	privateRoute.DELETE("/service-groups/:id", api.DeleteServiceGroup)
+	privateRoute.PUT("/service-dependencies/:id", api.SetServiceDependencies)
+	privateRoute.POST("/monitors", api.CreateMonitor)
+	privateRoute.PUT("/monitors/:id", api.EditMonitor)
+	privateRoute.DELETE("/monitors/:id", api.DeleteMonitor)
+	privateRoute.POST("/heartbeats", api.CreateHeartbeat)
+	privateRoute.PUT("/heartbeats/:id", api.EditHeartbeat)
+	privateRoute.DELETE("/heartbeats/:id", api.DeleteHeartbeat)
+	privateRoute.GET("/ingest-keys", api.GetIngestKeys)
+	privateRoute.POST("/ingest-keys", api.CreateIngestKey)
+	privateRoute.DELETE("/ingest-keys/:id", api.DeleteIngestKey)
+	privateRoute.POST("/slos", api.CreateSLO)
+	privateRoute.PUT("/slos/:id", api.EditSLO)
+	privateRoute.DELETE("/slos/:id", api.DeleteSLO)
+	privateRoute.PUT("/status-page", api.SetStatusPage)
+	privateRoute.POST("/incident-attachments/:id", api.UploadAttachment)
+	privateRoute.DELETE("/incident-attachments/:id/:attachmentId", api.DeleteAttachment)
 	privateRoute.DELETE("/delete-service/:id", api.DeleteService)
 	privateRoute.DELETE("/delete-incident/:id", api.DeleteIncident)
 
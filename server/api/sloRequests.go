@@ -0,0 +1,210 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	middlewares "github.com/krnveersharma/Statuses/midlewares"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/krnveersharma/Statuses/slo"
+)
+
+// GetSLOs lists the org's SLOs with their current error budget and burn
+// rates.
+func (a *Api) GetSLOs(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	slos, err := dbrequests.GetSLOsForOrg(a.DB, clerkUser.Org.ID)
+	if err != nil {
+		log.Println("Error in fetching SLOs:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch SLOs"})
+		return
+	}
+
+	statuses, err := a.evaluateSLOs(clerkUser.Org.ID, slos)
+	if err != nil {
+		log.Println("Error in evaluating SLOs:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch SLOs"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statuses)
+}
+
+func (a *Api) GetSLO(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	sloID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO id"})
+		return
+	}
+
+	o, err := dbrequests.GetSLO(a.DB, sloID, clerkUser.Org.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+		} else {
+			log.Println("Error in fetching SLO:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch SLO"})
+		}
+		return
+	}
+
+	statuses, err := a.evaluateSLOs(clerkUser.Org.ID, []Schemas.SLO{o})
+	if err != nil {
+		log.Println("Error in evaluating SLO:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch SLO"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statuses[0])
+}
+
+func (a *Api) CreateSLO(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	request, ok := a.bindSLORequest(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+
+	o, err := dbrequests.CreateSLO(a.DB, request, clerkUser.Org.ID)
+	if err != nil {
+		if errors.Is(err, dbrequests.ErrSLOExists) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Service already has an SLO", "details": "edit the existing SLO instead"})
+		} else {
+			log.Println("Error in creating SLO:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create SLO"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, o)
+}
+
+// EditSLO replaces an SLO's definition; burn alerts start over.
+func (a *Api) EditSLO(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	sloID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO id"})
+		return
+	}
+	request, ok := a.bindSLORequest(ctx, clerkUser.Org.ID)
+	if !ok {
+		return
+	}
+
+	o, err := dbrequests.UpdateSLO(a.DB, sloID, clerkUser.Org.ID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, dbrequests.ErrSLOExists):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Service already has an SLO"})
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+		default:
+			log.Println("Error in updating SLO:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update SLO"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, o)
+}
+
+func (a *Api) DeleteSLO(ctx *gin.Context) {
+	clerkUserRaw, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	clerkUser := clerkUserRaw.(*middlewares.UserData)
+
+	sloID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO id"})
+		return
+	}
+
+	if err := dbrequests.DeleteSLO(a.DB, sloID, clerkUser.Org.ID); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete SLO", "details": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "SLO deleted successfully"})
+}
+
+// bindSLORequest binds and normalizes an SLO request and checks that its
+// service belongs to the org, writing an error response and returning false
+// otherwise.
+func (a *Api) bindSLORequest(ctx *gin.Context, orgID string) (Schemas.SLORequest, bool) {
+	var request Schemas.SLORequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return request, false
+	}
+	if err := request.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return request, false
+	}
+	serviceID, ok := a.serviceRef(ctx, orgID, request.Service)
+	request.ServiceID = serviceID
+	return request, ok
+}
+
+// evaluateSLOs computes the current status of SLOs of one org, loading the
+// status history their longest window needs.
+func (a *Api) evaluateSLOs(orgID string, slos []Schemas.SLO) ([]Schemas.SLOStatus, error) {
+	statuses := []Schemas.SLOStatus{}
+	if len(slos) == 0 {
+		return statuses, nil
+	}
+
+	now := time.Now()
+	from := now
+	for _, o := range slos {
+		if start, _ := slo.Window(o, now); start.Before(from) {
+			from = start
+		}
+	}
+	history, err := dbrequests.GetStatusHistoryForOrg(a.DB, orgID, from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range slos {
+		statuses = append(statuses, slo.Evaluate(o, history[o.ServiceID], now))
+	}
+	return statuses, nil
+}
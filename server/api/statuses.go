@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// GetStatuses lists the service statuses, incident statuses and impact
+// levels with display labels and colours.
+func (a *Api) GetStatuses(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, Schemas.Statuses())
+}
+
+// respondBindError answers a body that failed to bind: 422 when it carried
+// an unknown status, 400 otherwise.
+func respondBindError(ctx *gin.Context, err error) {
+	var invalid *Schemas.InvalidStatusError
+	if errors.As(err, &invalid) {
+		respondInvalidStatus(ctx, invalid)
+		return
+	}
+	ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+}
+
+// respondInvalidStatus writes the 422 shared by every endpoint that takes a
+// status.
+func respondInvalidStatus(ctx *gin.Context, err *Schemas.InvalidStatusError) {
+	ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":   "Invalid status",
+		"details": err.Error(),
+		"allowed": err.Allowed,
+	})
+}
+
+// requireServiceStatus writes a 422 and returns false unless status is a
+// known service status.
+func requireServiceStatus(ctx *gin.Context, status Schemas.ServiceStatus) bool {
+	if status.Valid() {
+		return true
+	}
+	respondInvalidStatus(ctx, Schemas.NewInvalidServiceStatus(string(status)))
+	return false
+}
+
+// requireIncidentStatus writes a 422 and returns false unless status is a
+// known incident status.
+func requireIncidentStatus(ctx *gin.Context, status Schemas.IncidentStatus) bool {
+	if status.Valid() {
+		return true
+	}
+	respondInvalidStatus(ctx, Schemas.NewInvalidIncidentStatus(string(status)))
+	return false
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// Statuspage-compatible endpoints: the same data as GetPublicSummary in the
+// shapes of Atlassian Statuspage's /api/v2, so existing integrations work
+// unchanged.
+
+func (a *Api) GetStatuspageSummary(ctx *gin.Context) {
+	a.serveStatuspage(ctx, func(summary Schemas.StatuspageSummary) any {
+		return summary
+	})
+}
+
+func (a *Api) GetStatuspageStatus(ctx *gin.Context) {
+	a.serveStatuspage(ctx, func(summary Schemas.StatuspageSummary) any {
+		return gin.H{"page": summary.Page, "status": summary.Status}
+	})
+}
+
+func (a *Api) GetStatuspageComponents(ctx *gin.Context) {
+	a.serveStatuspage(ctx, func(summary Schemas.StatuspageSummary) any {
+		return gin.H{"page": summary.Page, "components": summary.Components}
+	})
+}
+
+func (a *Api) GetStatuspageUnresolvedIncidents(ctx *gin.Context) {
+	a.serveStatuspage(ctx, func(summary Schemas.StatuspageSummary) any {
+		return gin.H{"page": summary.Page, "incidents": summary.Incidents}
+	})
+}
+
+// serveStatuspage loads the org's public summary, converts it and serves the
+// part pick selects, cached like the other public endpoints.
+func (a *Api) serveStatuspage(ctx *gin.Context, pick func(Schemas.StatuspageSummary) any) {
+	a.servePublic(ctx, "application/json; charset=utf-8", func() ([]byte, bool) {
+		orgID, ok := a.publicOrg(ctx)
+		if !ok {
+			return nil, false
+		}
+
+		summary, err := a.loadStatusPageSummary(orgID, ctx.Param("orgSlug"))
+		if err != nil {
+			log.Println("Error in loading status page:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load status page"})
+			return nil, false
+		}
+		return marshalPublic(ctx, pick(toStatuspage(summary, a.publicPageURL(ctx.Param("orgSlug")))))
+	})
+}
+
+// publicPageURL is the URL of the org's public summary. It is absolute when
+// PUBLIC_URL is set and a path otherwise.
+func (a *Api) publicPageURL(orgSlug string) string {
+	return a.Config.PublicURL + "/public/" + orgSlug + "/summary"
+}
+
+// statuspageIndicators maps the overall status to Statuspage's indicator and
+// description.
+var statuspageIndicators = map[Schemas.ServiceStatus]Schemas.StatuspageStatus{
+	Schemas.ServiceOperational:      {Indicator: "none", Description: "All Systems Operational"},
+	Schemas.ServiceUnderMaintenance: {Indicator: "maintenance", Description: "Service Under Maintenance"},
+	Schemas.ServiceDegraded:         {Indicator: "minor", Description: "Minor Service Outage"},
+	Schemas.ServicePartialOutage:    {Indicator: "major", Description: "Partial System Outage"},
+	Schemas.ServiceMajorOutage:      {Indicator: "critical", Description: "Major System Outage"},
+}
+
+// statuspageImpacts maps the worst impact on affected services to an
+// incident impact.
+var statuspageImpacts = map[string]string{
+	string(Schemas.ServiceDegraded):      "minor",
+	string(Schemas.ServicePartialOutage): "major",
+	string(Schemas.ServiceMajorOutage):   "critical",
+}
+
+// componentStatus turns "partial outage" into "partial_outage" and so on.
+func componentStatus(status Schemas.ServiceStatus) string {
+	return strings.ReplaceAll(string(status), " ", "_")
+}
+
+func toStatuspage(summary Schemas.StatusPageSummary, pageURL string) Schemas.StatuspageSummary {
+	pageID := summary.Page.Slug
+	page := Schemas.StatuspagePage{
+		ID:       pageID,
+		Name:     summary.Page.Slug,
+		URL:      pageURL,
+		TimeZone: "Etc/UTC",
+	}
+
+	components := []Schemas.StatuspageComponent{}
+	byService := map[int]Schemas.StatuspageComponent{}
+	members := map[int][]string{}
+	groupTimes := map[int][2]time.Time{}
+	for i, service := range summary.Services {
+		component := Schemas.StatuspageComponent{
+			ID:        strconv.Itoa(service.ID),
+			Name:      service.Name,
+			Status:    componentStatus(service.Status),
+			CreatedAt: service.CreatedAt,
+			UpdatedAt: service.UpdatedAt,
+			Position:  i + 1,
+			PageID:    pageID,
+			Showcase:  true,
+		}
+		if service.Description != "" {
+			description := service.Description
+			component.Description = &description
+		}
+		if service.GroupID != nil {
+			groupID := groupComponentID(*service.GroupID)
+			component.GroupID = &groupID
+			members[*service.GroupID] = append(members[*service.GroupID], component.ID)
+
+			times, ok := groupTimes[*service.GroupID]
+			if !ok || service.CreatedAt.Before(times[0]) {
+				times[0] = service.CreatedAt
+			}
+			if service.UpdatedAt.After(times[1]) {
+				times[1] = service.UpdatedAt
+			}
+			groupTimes[*service.GroupID] = times
+		}
+		byService[service.ID] = component
+		components = append(components, component)
+		page.UpdatedAt = latest(page.UpdatedAt, service.UpdatedAt)
+	}
+	for _, group := range summary.Groups {
+		components = append(components, Schemas.StatuspageComponent{
+			ID:         groupComponentID(group.ID),
+			Name:       group.Name,
+			Status:     componentStatus(group.Status),
+			CreatedAt:  groupTimes[group.ID][0],
+			UpdatedAt:  groupTimes[group.ID][1],
+			Position:   group.Position,
+			PageID:     pageID,
+			Group:      true,
+			Components: members[group.ID],
+		})
+	}
+
+	result := Schemas.StatuspageSummary{
+		Page:                  page,
+		Components:            components,
+		Incidents:             []Schemas.StatuspageIncident{},
+		ScheduledMaintenances: []Schemas.StatuspageIncident{},
+		Status:                statuspageIndicators[summary.Status],
+	}
+	for _, incident := range summary.Incidents {
+		converted := toStatuspageIncident(incident, pageID, byService, summary.GeneratedAt)
+		if incident.Status == Schemas.IncidentMaintenance {
+			result.ScheduledMaintenances = append(result.ScheduledMaintenances, converted)
+		} else {
+			result.Incidents = append(result.Incidents, converted)
+		}
+		result.Page.UpdatedAt = latest(result.Page.UpdatedAt, incident.UpdatedAt)
+	}
+	for _, incident := range summary.UpcomingMaintenance {
+		converted := toStatuspageIncident(incident, pageID, byService, summary.GeneratedAt)
+		result.ScheduledMaintenances = append(result.ScheduledMaintenances, converted)
+		result.Page.UpdatedAt = latest(result.Page.UpdatedAt, incident.UpdatedAt)
+	}
+	if result.Page.UpdatedAt.IsZero() {
+		result.Page.UpdatedAt = summary.GeneratedAt
+	}
+
+	return result
+}
+
+// toStatuspageIncident converts an incident, or a maintenance, which becomes
+// "scheduled" before its start and "in_progress" after it.
+func toStatuspageIncident(incident Schemas.PublicIncident, pageID string, byService map[int]Schemas.StatuspageComponent, now time.Time) Schemas.StatuspageIncident {
+	maintenance := incident.Status == Schemas.IncidentMaintenance
+	status := func(s Schemas.IncidentStatus) string {
+		switch {
+		case s == Schemas.IncidentResolved && maintenance:
+			return "completed"
+		case s == Schemas.IncidentMaintenance && incident.StartedAt.After(now):
+			return "scheduled"
+		case s == Schemas.IncidentMaintenance:
+			return "in_progress"
+		default:
+			return string(s)
+		}
+	}
+
+	converted := Schemas.StatuspageIncident{
+		ID:              incident.ID,
+		Name:            incident.Title,
+		Status:          status(incident.Status),
+		CreatedAt:       incident.StartedAt,
+		UpdatedAt:       incident.UpdatedAt,
+		ResolvedAt:      incident.ResolvedAt,
+		Impact:          "none",
+		StartedAt:       incident.StartedAt,
+		PageID:          pageID,
+		IncidentUpdates: []Schemas.StatuspageIncidentUpdate{},
+		Components:      []Schemas.StatuspageComponent{},
+	}
+	if maintenance {
+		converted.Impact = "maintenance"
+		startedAt := incident.StartedAt
+		converted.ScheduledFor = &startedAt
+	}
+
+	worst := ""
+	for _, affected := range incident.AffectedServices {
+		if component, ok := byService[affected.ID]; ok {
+			converted.Components = append(converted.Components, component)
+		}
+		if Schemas.ServiceStatus(affected.Impact).Worse(Schemas.ServiceStatus(worst)) {
+			worst = affected.Impact
+		}
+	}
+	if impact, ok := statuspageImpacts[worst]; ok && !maintenance {
+		converted.Impact = impact
+	}
+
+	for _, update := range incident.Updates {
+		converted.IncidentUpdates = append(converted.IncidentUpdates, Schemas.StatuspageIncidentUpdate{
+			ID:                 update.ID,
+			Status:             status(update.Status),
+			Body:               update.Body,
+			IncidentID:         incident.ID,
+			CreatedAt:          update.CreatedAt,
+			UpdatedAt:          update.CreatedAt,
+			DisplayAt:          update.CreatedAt,
+			AffectedComponents: []Schemas.StatuspageAffected{},
+		})
+		// Updates are newest first, so the last one seen is the earliest.
+		if update.Status == Schemas.IncidentMonitoring {
+			createdAt := update.CreatedAt
+			converted.MonitoringAt = &createdAt
+		}
+	}
+
+	return converted
+}
+
+func groupComponentID(groupID int) string {
+	return "group-" + strconv.Itoa(groupID)
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
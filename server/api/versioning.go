@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidIfMatch = errors.New(`If-Match must be an ETag returned by the server, such as "3"`)
+
+// expectedVersion returns the version the client based its edit on. The
+// If-Match header wins over the version field in the body; a zero body
+// version counts as absent since stored versions start at 1. An If-Match the
+// server did not issue is an error rather than absent, so a client that asked
+// for a guarded write never gets an unguarded one.
+func expectedVersion(ctx *gin.Context, bodyVersion int) (int, bool, error) {
+	if ifMatch := ctx.GetHeader("If-Match"); ifMatch != "" {
+		tag := strings.TrimPrefix(strings.TrimSpace(ifMatch), "W/")
+		version, err := strconv.Atoi(strings.Trim(tag, `"`))
+		if err != nil || version < 1 {
+			return 0, false, errInvalidIfMatch
+		}
+		return version, true, nil
+	}
+	if bodyVersion > 0 {
+		return bodyVersion, true, nil
+	}
+	return 0, false, nil
+}
+
+// setETag exposes the row version so clients can echo it back in If-Match.
+func setETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
@@ -0,0 +1,18 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no blob exists under a key.
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps attachment bytes outside Postgres. Keys are slash-separated
+// paths chosen by the caller, e.g. "incidents/12/3f9c…".
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +13,11 @@ type Config struct {
 	ClerkPublishableKey string
 	ClerkSecretKey      string
 	AllowedHost         string
+	AttachmentsDir      string
+	// PublicURL is the scheme and host public pages are reached at, e.g.
+	// https://status.example.com. Absolute links in public responses are
+	// built from it, never from request headers.
+	PublicURL string
 }
 
 func SetupConfig() *Config {
@@ -22,5 +28,14 @@ func SetupConfig() *Config {
 		ClerkPublishableKey: os.Getenv("CLERK_PUBLISHABLE_KEY"),
 		ClerkSecretKey:      os.Getenv("CLERK_SECRET_KEY"),
 		AllowedHost:         os.Getenv("ALLOWED_HOST"),
+		AttachmentsDir:      getEnvOrDefault("ATTACHMENTS_DIR", "attachments"),
+		PublicURL:           strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/"),
 	}
 }
+
+func getEnvOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,86 @@
+package dbrequests
+
+import (
+	"database/sql"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+const attachmentColumns = "a.id, a.incident_id, a.update_id, a.file_name, a.content_type, a.size_bytes, a.storage_key, a.created_at, a.created_by_clerk"
+
+func scanAttachment(row rowScanner, attachment *Schemas.Attachment) error {
+	return row.Scan(
+		&attachment.ID,
+		&attachment.IncidentID,
+		&attachment.UpdateID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.SizeBytes,
+		&attachment.StorageKey,
+		&attachment.CreatedAt,
+		&attachment.CreatedByClerk,
+	)
+}
+
+// AddAttachment stores attachment metadata for an incident in the org. A set
+// UpdateID must belong to the same incident. It returns sql.ErrNoRows when the
+// incident (or update) is not found.
+func AddAttachment(db *sql.DB, orgID string, attachment Schemas.Attachment) (Schemas.Attachment, error) {
+	query := `
+		INSERT INTO incident_attachments AS a
+			(incident_id, update_id, file_name, content_type, size_bytes, storage_key, created_by_clerk)
+		SELECT i.id, $3, $4, $5, $6, $7, $8 FROM incidents i
+		WHERE i.id = $1 AND i.clerk_org_id = $2
+		AND ($3::integer IS NULL OR EXISTS (
+			SELECT 1 FROM incident_updates u WHERE u.id = $3 AND u.incident_id = i.id
+		))
+		RETURNING ` + attachmentColumns
+
+	var saved Schemas.Attachment
+	err := scanAttachment(db.QueryRow(query,
+		attachment.IncidentID,
+		orgID,
+		attachment.UpdateID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.SizeBytes,
+		attachment.StorageKey,
+		attachment.CreatedByClerk,
+	), &saved)
+	return saved, err
+}
+
+func GetAttachments(db *sql.DB, incidentID string) ([]Schemas.Attachment, error) {
+	rows, err := db.Query(`SELECT `+attachmentColumns+` FROM incident_attachments a
+		WHERE a.incident_id = $1 ORDER BY a.created_at, a.id`, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []Schemas.Attachment{}
+	for rows.Next() {
+		var attachment Schemas.Attachment
+		if err := scanAttachment(rows, &attachment); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func GetAttachment(db *sql.DB, incidentID, attachmentID, orgID string) (Schemas.Attachment, error) {
+	var attachment Schemas.Attachment
+	err := scanAttachment(db.QueryRow(`
+		SELECT `+attachmentColumns+` FROM incident_attachments a
+		JOIN incidents i ON i.id = a.incident_id
+		WHERE a.id = $1 AND a.incident_id = $2 AND i.clerk_org_id = $3
+	`, attachmentID, incidentID, orgID), &attachment)
+	return attachment, err
+}
+
+func DeleteAttachment(db *sql.DB, attachmentID string) error {
+	_, err := db.Exec(`DELETE FROM incident_attachments WHERE id = $1`, attachmentID)
+	return err
+}
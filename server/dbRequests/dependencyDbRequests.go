@@ -0,0 +1,69 @@
+package dbrequests
+
+import (
+	"database/sql"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+func GetServiceDependencies(db Executor, orgID string) ([]Schemas.ServiceDependency, error) {
+	rows, err := db.Query(`
+		SELECT d.service_id, d.depends_on_id
+		FROM service_dependencies d
+		JOIN services s ON s.id = d.service_id
+		WHERE s.clerk_org_id = $1
+		ORDER BY d.service_id, d.depends_on_id
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dependencies []Schemas.ServiceDependency
+	for rows.Next() {
+		var d Schemas.ServiceDependency
+		if err := rows.Scan(&d.ServiceID, &d.DependsOnID); err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, d)
+	}
+
+	return dependencies, rows.Err()
+}
+
+// SetServiceDependencies replaces what serviceID depends on. Writes are
+// serialized per org, and validate sees the org's current dependencies before
+// anything changes; its error aborts the write.
+func SetServiceDependencies(db *sql.DB, orgID string, serviceID int, dependsOn []int, validate func([]Schemas.ServiceDependency) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, "service_dependencies:"+orgID); err != nil {
+		return err
+	}
+	current, err := GetServiceDependencies(tx, orgID)
+	if err != nil {
+		return err
+	}
+	if err := validate(current); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM service_dependencies WHERE service_id = $1`, serviceID); err != nil {
+		return err
+	}
+	for _, dependsOnID := range dependsOn {
+		_, err := tx.Exec(
+			`INSERT INTO service_dependencies (service_id, depends_on_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			serviceID, dependsOnID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
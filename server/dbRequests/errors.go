@@ -0,0 +1,34 @@
+package dbrequests
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrVersionConflict is returned when a write carries a version that no
+// longer matches the stored row, i.e. someone else edited it first.
+var ErrVersionConflict = errors.New("version conflict: resource was modified by another request")
+
+// ErrAlreadyMerged is returned when an incident taking part in a merge has
+// itself already been merged into another incident.
+var ErrAlreadyMerged = errors.New("incident has already been merged into another incident")
+
+// ErrSlugTaken is returned when another service of the org already uses the
+// requested slug.
+var ErrSlugTaken = errors.New("slug is already used by another service")
+
+// ErrServiceInUse is returned when deleting a service that unresolved
+// incidents still reference.
+var ErrServiceInUse = errors.New("service is referenced by unresolved incidents")
+
+// Executor is satisfied by both *sql.DB and *sql.Tx so helpers can run
+// inside or outside a transaction.
+type Executor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// ErrUnknownEscalationStep is returned when a saved policy names a step ID
+// the org's current policy does not have.
+var ErrUnknownEscalationStep = errors.New("step id does not match a step of the current policy")
@@ -0,0 +1,198 @@
+package dbrequests
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/lib/pq"
+)
+
+func GetEscalationPolicy(db *sql.DB, orgID string) (Schemas.EscalationPolicy, error) {
+	var policy Schemas.EscalationPolicy
+	var steps []byte
+
+	err := db.QueryRow(
+		`SELECT steps, updated_at FROM escalation_policies WHERE clerk_org_id = $1`, orgID,
+	).Scan(&steps, &policy.UpdatedAt)
+	if err != nil {
+		return policy, err
+	}
+
+	return policy, json.Unmarshal(steps, &policy.Steps)
+}
+
+// SaveEscalationPolicy replaces the org's policy. Steps sent with an ID keep
+// it and must name a step of the current policy; steps without one take the
+// ID of an unclaimed current step with the same delay and target, or get a
+// new one. Fired steps are recorded by ID, so only new steps fire again for
+// incidents already open.
+func SaveEscalationPolicy(db *sql.DB, orgID string, policy Schemas.EscalationPolicy) (Schemas.EscalationPolicy, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return policy, err
+	}
+	defer tx.Rollback()
+
+	var stored []Schemas.EscalationStep
+	var current []byte
+	err = tx.QueryRow(
+		`SELECT steps FROM escalation_policies WHERE clerk_org_id = $1 FOR UPDATE`, orgID,
+	).Scan(&current)
+	if err != nil && err != sql.ErrNoRows {
+		return policy, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(current, &stored); err != nil {
+			return policy, err
+		}
+	}
+
+	claimed := map[string]bool{}
+	for _, step := range policy.Steps {
+		if step.ID == "" {
+			continue
+		}
+		if !slices.ContainsFunc(stored, func(s Schemas.EscalationStep) bool { return s.ID == step.ID }) {
+			return policy, ErrUnknownEscalationStep
+		}
+		claimed[step.ID] = true
+	}
+	for i, step := range policy.Steps {
+		if step.ID != "" {
+			continue
+		}
+		for _, s := range stored {
+			if !claimed[s.ID] && s.AfterMinutes == step.AfterMinutes && s.Notify == step.Notify {
+				step.ID = s.ID
+				break
+			}
+		}
+		if step.ID == "" {
+			if step.ID, err = newEscalationStepID(); err != nil {
+				return policy, err
+			}
+		}
+		claimed[step.ID] = true
+		policy.Steps[i] = step
+	}
+
+	steps, err := json.Marshal(policy.Steps)
+	if err != nil {
+		return policy, err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO escalation_policies (clerk_org_id, steps, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (clerk_org_id) DO UPDATE SET steps = EXCLUDED.steps, updated_at = NOW()
+		RETURNING updated_at
+	`, orgID, string(steps)).Scan(&policy.UpdatedAt)
+	if err != nil {
+		return policy, err
+	}
+
+	return policy, tx.Commit()
+}
+
+func newEscalationStepID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func DeleteEscalationPolicy(db *sql.DB, orgID string) error {
+	_, err := db.Exec(`DELETE FROM escalation_policies WHERE clerk_org_id = $1`, orgID)
+	return err
+}
+
+// UnacknowledgedIncident is an incident still in "investigating" in an org
+// with an escalation policy, along with the steps already fired for it.
+type UnacknowledgedIncident struct {
+	IncidentID string
+	OrgID      string
+	Title      string
+	Status     Schemas.IncidentStatus
+	OpenedAt   time.Time
+	Steps      []Schemas.EscalationStep
+	FiredSteps []string
+}
+
+func GetUnacknowledgedIncidents(ctx context.Context, db *sql.DB) ([]UnacknowledgedIncident, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT i.id, i.clerk_org_id, i.title, i.status, i.created_at, p.steps,
+			ARRAY(SELECT e.step_id FROM incident_escalations e WHERE e.incident_id = i.id)
+		FROM incidents i
+		JOIN escalation_policies p ON p.clerk_org_id = i.clerk_org_id
+		WHERE i.status = 'investigating' AND i.merged_into IS NULL
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []UnacknowledgedIncident
+	for rows.Next() {
+		var incident UnacknowledgedIncident
+		var steps []byte
+		err := rows.Scan(
+			&incident.IncidentID,
+			&incident.OrgID,
+			&incident.Title,
+			&incident.Status,
+			&incident.OpenedAt,
+			&steps,
+			pq.Array(&incident.FiredSteps),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(steps, &incident.Steps); err != nil {
+			return nil, fmt.Errorf("decoding escalation steps for org %s: %w", incident.OrgID, err)
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
+
+// RecordEscalation marks a step as fired for the incident and writes an
+// internal timeline entry for it. It returns false when the step had already
+// been recorded, e.g. by another server instance.
+func RecordEscalation(ctx context.Context, db *sql.DB, incidentID string, status Schemas.IncidentStatus, stepID string, note string) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO incident_escalations (incident_id, step_id, escalated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (incident_id, step_id) DO NOTHING
+	`, incidentID, stepID)
+	if err != nil {
+		return false, err
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+		return false, err
+	}
+
+	message, err := json.Marshal(Schemas.TimelineNote{Note: note})
+	if err != nil {
+		return false, err
+	}
+	if err := InsertTimelineEntry(tx, string(message), incidentID, status, Schemas.VisibilityInternal, "system", "Escalation"); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,193 @@
+package dbrequests
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// heartbeatColumns is the column list scanHeartbeat expects; queries alias
+// heartbeats as h and join services as s.
+const heartbeatColumns = `h.id, h.service_id, h.name, h.grace_seconds, h.failure_status, h.open_incident,
+	h.last_ping_at, h.tripped, h.incident_id, h.created_at, s.clerk_org_id`
+
+func scanHeartbeat(row rowScanner, extra ...any) (Schemas.Heartbeat, error) {
+	var h Schemas.Heartbeat
+	dest := []any{
+		&h.ID, &h.ServiceID, &h.Name, &h.GraceSeconds, &h.FailureStatus, &h.OpenIncident,
+		&h.LastPingAt, &h.Tripped, &h.IncidentID, &h.CreatedAt, &h.OrgID,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return h, err
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateHeartbeat stores a normalized request with the hash of token. The
+// caller checks that the service belongs to the org.
+func CreateHeartbeat(db *sql.DB, request Schemas.HeartbeatRequest, token string) (Schemas.Heartbeat, error) {
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO heartbeats (service_id, name, token_hash, grace_seconds, failure_status, open_incident)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, request.ServiceID, request.Name, hashToken(token), request.GraceSeconds, request.FailureStatus, *request.OpenIncident,
+	).Scan(&id)
+	if err != nil {
+		return Schemas.Heartbeat{}, err
+	}
+
+	heartbeat, err := scanHeartbeat(db.QueryRow(`SELECT `+heartbeatColumns+` FROM heartbeats h JOIN services s ON s.id = h.service_id WHERE h.id = $1`, id))
+	heartbeat.Token = token
+	return heartbeat, err
+}
+
+func GetHeartbeatsForOrg(db *sql.DB, orgID string) ([]Schemas.Heartbeat, error) {
+	rows, err := db.Query(`
+		SELECT `+heartbeatColumns+`
+		FROM heartbeats h JOIN services s ON s.id = h.service_id
+		WHERE s.clerk_org_id = $1
+		ORDER BY h.service_id, h.id
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var heartbeats []Schemas.Heartbeat
+	for rows.Next() {
+		h, err := scanHeartbeat(rows)
+		if err != nil {
+			return nil, err
+		}
+		heartbeats = append(heartbeats, h)
+	}
+	return heartbeats, rows.Err()
+}
+
+// UpdateHeartbeat changes a heartbeat's settings, keeping its token and
+// state. A tripped heartbeat that is moved to another service or given
+// another failure status lets go of its service: its state is cleared and it
+// is returned as it was before, so the caller can resolve its incident and
+// restore the service. It returns sql.ErrNoRows when the org has no such
+// heartbeat.
+func UpdateHeartbeat(db *sql.DB, heartbeatID int, orgID string, request Schemas.HeartbeatRequest) (Schemas.Heartbeat, *Schemas.Heartbeat, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return Schemas.Heartbeat{}, nil, err
+	}
+	defer tx.Rollback()
+
+	previous, err := scanHeartbeat(tx.QueryRow(`
+		SELECT `+heartbeatColumns+`
+		FROM heartbeats h JOIN services s ON s.id = h.service_id
+		WHERE h.id = $1 AND s.clerk_org_id = $2
+		FOR UPDATE OF h
+	`, heartbeatID, orgID))
+	if err != nil {
+		return Schemas.Heartbeat{}, nil, err
+	}
+
+	released := previous.Tripped && (request.ServiceID != previous.ServiceID || request.FailureStatus != previous.FailureStatus)
+	heartbeat, err := scanHeartbeat(tx.QueryRow(`
+		UPDATE heartbeats h
+		SET service_id = $1, name = $2, grace_seconds = $3, failure_status = $4, open_incident = $5,
+			tripped = tripped AND NOT $7,
+			incident_id = CASE WHEN $7 THEN NULL ELSE incident_id END
+		FROM services s
+		WHERE s.id = $1 AND h.id = $6
+		RETURNING `+heartbeatColumns,
+		request.ServiceID, request.Name, request.GraceSeconds, request.FailureStatus, *request.OpenIncident, heartbeatID, released,
+	))
+	if err != nil {
+		return Schemas.Heartbeat{}, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Schemas.Heartbeat{}, nil, err
+	}
+
+	if !released {
+		return heartbeat, nil, nil
+	}
+	return heartbeat, &previous, nil
+}
+
+// DeleteHeartbeat removes a heartbeat and returns it as it was, so the caller
+// can let go of its service if it was tripped. It returns sql.ErrNoRows when
+// the org has no such heartbeat.
+func DeleteHeartbeat(db *sql.DB, heartbeatID int, orgID string) (Schemas.Heartbeat, error) {
+	return scanHeartbeat(db.QueryRow(`
+		WITH h AS (
+			DELETE FROM heartbeats h USING services s
+			WHERE s.id = h.service_id AND h.id = $1 AND s.clerk_org_id = $2
+			RETURNING h.*
+		)
+		SELECT `+heartbeatColumns+`
+		FROM h JOIN services s ON s.id = h.service_id
+	`, heartbeatID, orgID))
+}
+
+// RecordHeartbeatPing stamps the heartbeat with the given token and clears
+// its tripped state. It returns the heartbeat as it was before the ping, so
+// callers can tell whether it had tripped, or sql.ErrNoRows for an unknown
+// token.
+func RecordHeartbeatPing(ctx context.Context, db *sql.DB, token string) (Schemas.Heartbeat, error) {
+	return scanHeartbeat(db.QueryRowContext(ctx, `
+		WITH h AS (
+			SELECT * FROM heartbeats WHERE token_hash = $1 FOR UPDATE
+		), updated AS (
+			UPDATE heartbeats SET last_ping_at = NOW(), tripped = false, incident_id = NULL
+			FROM h WHERE heartbeats.id = h.id
+		)
+		SELECT `+heartbeatColumns+`
+		FROM h JOIN services s ON s.id = h.service_id
+	`, hashToken(token)))
+}
+
+// ClaimOverdueHeartbeats marks as tripped, and returns, the heartbeats that
+// have been silent for longer than their grace period since their last ping
+// (or creation). Each overdue heartbeat is returned once.
+func ClaimOverdueHeartbeats(ctx context.Context, db *sql.DB) ([]Schemas.Heartbeat, error) {
+	rows, err := db.QueryContext(ctx, `
+		UPDATE heartbeats h
+		SET tripped = true
+		FROM services s
+		WHERE s.id = h.service_id AND NOT h.tripped
+		AND COALESCE(h.last_ping_at, h.created_at) + h.grace_seconds * interval '1 second' <= NOW()
+		RETURNING `+heartbeatColumns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var heartbeats []Schemas.Heartbeat
+	for rows.Next() {
+		h, err := scanHeartbeat(rows)
+		if err != nil {
+			return nil, err
+		}
+		heartbeats = append(heartbeats, h)
+	}
+	return heartbeats, rows.Err()
+}
+
+// SetHeartbeatIncident records the incident opened for a tripped heartbeat,
+// if any, and reports whether it is still tripped: false when a ping cleared
+// it or it was deleted meanwhile.
+func SetHeartbeatIncident(ctx context.Context, db *sql.DB, heartbeatID int, incidentID *string) (bool, error) {
+	result, err := db.ExecContext(ctx,
+		`UPDATE heartbeats SET incident_id = $2 WHERE id = $1 AND tripped`,
+		heartbeatID, incidentID,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
@@ -3,10 +3,13 @@ package dbrequests
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 
 	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/lib/pq"
 )
 
 func CreateIncident(
@@ -16,8 +19,9 @@ func CreateIncident(
 ) (string, error) {
 
 	query := `
-		INSERT INTO incidents (title, description, status, started_at, clerk_org_id, created_by_clerk)
-		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
+		INSERT INTO incidents (title, description, status, started_at, clerk_org_id, created_by_clerk, labels, severity, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, '{}'::jsonb), NULLIF($8, ''), CASE WHEN $3 = 'resolved' THEN NOW() END)
+		RETURNING id
 	`
 
 	var id string
@@ -28,6 +32,8 @@ func CreateIncident(
 		incident.StartedAt,
 		clerkOrgID,
 		createdBy,
+		incident.Labels,
+		incident.Severity,
 	).Scan(&id)
 
 	if err != nil {
@@ -36,12 +42,21 @@ func CreateIncident(
 	return id, err
 }
 
-func GetIncidentsForOrg(db *sql.DB, orgID string) ([]Schemas.IncidentTitles, error) {
-	rows, err := db.Query(`
-		SELECT id, title, status, created_at 
+// GetIncidentsForOrg lists the org's open incidents, keeping only those
+// carrying all of the given labels when labels is non-empty.
+func GetIncidentsForOrg(db *sql.DB, orgID string, labels Schemas.Labels) ([]Schemas.IncidentTitles, error) {
+	query := `
+		SELECT id, title, status, created_at, labels, COALESCE(severity, '')
 		FROM incidents 
-		WHERE clerk_org_id = $1 AND status != 'resolved'
-	`, orgID)
+		WHERE clerk_org_id = $1 AND status != 'resolved' AND merged_into IS NULL
+	`
+	args := []any{orgID}
+	if len(labels) > 0 {
+		query += " AND labels @> $2"
+		args = append(args, labels)
+	}
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +65,7 @@ func GetIncidentsForOrg(db *sql.DB, orgID string) ([]Schemas.IncidentTitles, err
 	var incidents []Schemas.IncidentTitles
 	for rows.Next() {
 		var i Schemas.IncidentTitles
-		if err := rows.Scan(&i.ID, &i.Title, &i.Status, &i.CreatedAt); err != nil {
+		if err := rows.Scan(&i.ID, &i.Title, &i.Status, &i.CreatedAt, &i.Labels, &i.Severity); err != nil {
 			return nil, err
 		}
 		incidents = append(incidents, i)
@@ -61,7 +76,8 @@ func GetIncidentsForOrg(db *sql.DB, orgID string) ([]Schemas.IncidentTitles, err
 
 func GetIncidentByID(db *sql.DB, incidentID string, orgID string) (*Schemas.Incident, error) {
 	query := `
-		SELECT id, title, description, status, started_at, resolved_at, created_at, updated_at, created_by_clerk
+		SELECT id, title, description, status, started_at, resolved_at, created_at, updated_at, created_by_clerk, version, merged_into, labels,
+			COALESCE(severity, '')
 		FROM incidents
 		WHERE id = $1 AND clerk_org_id = $2
 	`
@@ -79,6 +95,10 @@ func GetIncidentByID(db *sql.DB, incidentID string, orgID string) (*Schemas.Inci
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.CreatedByClerk,
+		&i.Version,
+		&i.MergedInto,
+		&i.Labels,
+		&i.Severity,
 	)
 
 	if err != nil {
@@ -87,23 +107,38 @@ func GetIncidentByID(db *sql.DB, incidentID string, orgID string) (*Schemas.Inci
 	return &i, nil
 }
 
-func LinkIncidentServices(db *sql.DB, incidentID string, links []Schemas.LinkedServiceIn) error {
+func LinkIncidentServices(db Executor, incidentID string, links []Schemas.LinkedServiceIn) error {
 	log.Printf("[LinkIncidentServices] Linking %d services to incident ID: %s", len(links), incidentID)
 
-	query := `INSERT INTO service_incidents (service_id, incident_id) VALUES ($1, $2)`
+	query := `INSERT INTO service_incidents (service_id, incident_id, impact) VALUES ($1, $2, $3)`
 	for _, link := range links {
-		log.Printf("[LinkIncidentServices] Linking service ID: %s", link.ServiceID)
+		log.Printf("[LinkIncidentServices] Linking service ID: %v", link.ServiceID)
 
-		_, err := db.Exec(query, link.ServiceID, incidentID)
+		impact := link.Impact
+		if impact == "" {
+			impact = Schemas.DefaultImpact
+		}
+		_, err := db.Exec(query, link.ServiceID, incidentID, impact)
 		if err != nil {
-			log.Printf("[LinkIncidentServices] ERROR inserting service_id=%s incident_id=%s: %v", link.ServiceID, incidentID, err)
-			return fmt.Errorf("inserting service_id=%s: %w", link.ServiceID, err)
+			log.Printf("[LinkIncidentServices] ERROR inserting service_id=%v incident_id=%s: %v", link.ServiceID, incidentID, err)
+			return fmt.Errorf("inserting service_id=%v: %w", link.ServiceID, err)
 		}
 	}
 	return nil
 }
 
-func UpdateIncident(ctx context.Context, db *sql.DB, orgID string, incident Schemas.EditInstance) error {
+// UpdateIncident overwrites the incident and its service links, but only if
+// the stored version still equals expectedVersion. Nil labels and an empty
+// severity keep the stored ones. It returns the new version, ErrVersionConflict when the row was
+// changed meanwhile, or sql.ErrNoRows when the incident does not exist in the
+// org.
+func UpdateIncident(ctx context.Context, db *sql.DB, orgID string, incident Schemas.EditInstance, expectedVersion int) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	// Update incident data
 	updateQuery := `
 		UPDATE incidents
@@ -111,45 +146,222 @@ func UpdateIncident(ctx context.Context, db *sql.DB, orgID string, incident Sche
 			description = $2,
 			status = $3,
 			started_at = $4,
-			updated_at = NOW()
-		WHERE id = $5 AND clerk_org_id = $6
+			labels = COALESCE($8, labels),
+			severity = COALESCE(NULLIF($9, ''), severity),
+			resolved_at = CASE WHEN $3 = 'resolved' THEN COALESCE(resolved_at, NOW()) END,
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $5 AND clerk_org_id = $6 AND version = $7
+		RETURNING version
 	`
-	_, err := db.ExecContext(ctx, updateQuery,
+	var version int
+	err = tx.QueryRowContext(ctx, updateQuery,
 		incident.Title,
 		incident.Description,
 		incident.Status,
 		incident.StartedAt,
 		incident.ID,
 		orgID,
-	)
+		expectedVersion,
+		incident.Labels,
+		incident.Severity,
+	).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, incidentMissingOrStale(tx, incident.ID, orgID)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update incident: %w", err)
+		return 0, fmt.Errorf("failed to update incident: %w", err)
 	}
-	log.Printf("[UpdateIncident] Updated incident ID: %s\n", incident.ID)
+	log.Printf("[UpdateIncident] Updated incident ID: %s to version %d\n", incident.ID, version)
 
-	// Delete old service links
-	deleteQuery := `DELETE FROM service_incidents WHERE incident_id = $1`
-	if _, err := db.ExecContext(ctx, deleteQuery, incident.ID); err != nil {
-		return fmt.Errorf("failed to delete old service links: %w", err)
+	if err := replaceIncidentLinks(ctx, tx, incident.ID, incident.LinkedServices); err != nil {
+		return 0, err
 	}
-	log.Printf("[UpdateIncident] Cleared old service links for incident ID: %s\n", incident.ID)
+	log.Printf("[UpdateIncident] Linked new services for incident ID: %s: %v\n", incident.ID, incident.LinkedServices)
 
-	// Link new services
-	if err := LinkIncidentServices(db, incident.ID, incident.LinkedServices); err != nil {
-		return fmt.Errorf("failed to link new services: %w", err)
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit incident update: %w", err)
+	}
+	return version, nil
+}
+
+// replaceIncidentLinks replaces all service links of an incident. A link
+// without an impact keeps the one stored for its service, so clients that do
+// not send impacts leave them alone; only new links get DefaultImpact.
+func replaceIncidentLinks(ctx context.Context, tx *sql.Tx, incidentID string, links []Schemas.LinkedServiceIn) error {
+	rows, err := tx.QueryContext(ctx, `SELECT service_id, impact FROM service_incidents WHERE incident_id = $1`, incidentID)
+	if err != nil {
+		return fmt.Errorf("failed to load old service links: %w", err)
+	}
+	stored := map[int32]Schemas.Impact{}
+	for rows.Next() {
+		var serviceID int32
+		var impact Schemas.Impact
+		if err := rows.Scan(&serviceID, &impact); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to load old service links: %w", err)
+		}
+		stored[serviceID] = impact
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load old service links: %w", err)
+	}
+
+	kept := make([]Schemas.LinkedServiceIn, len(links))
+	for i, link := range links {
+		if link.Impact == "" && link.ServiceID != nil {
+			link.Impact = stored[*link.ServiceID]
+		}
+		kept[i] = link
 	}
-	log.Printf("[UpdateIncident] Linked new services for incident ID: %s: %v\n", incident.ID, incident.LinkedServices)
 
+	if _, err := tx.ExecContext(ctx, `DELETE FROM service_incidents WHERE incident_id = $1`, incidentID); err != nil {
+		return fmt.Errorf("failed to delete old service links: %w", err)
+	}
+	if err := LinkIncidentServices(tx, incidentID, kept); err != nil {
+		return fmt.Errorf("failed to link new services: %w", err)
+	}
 	return nil
 }
 
-func UpdateIncidentUpdate(db *sql.DB, message, incidentId, status, userId, fullName string) error {
+// incidentMissingOrStale tells apart a guarded UPDATE that matched nothing
+// because the incident is gone from one that lost a version race.
+func incidentMissingOrStale(db Executor, incidentID, orgID string) error {
+	var exists bool
+	err := db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM incidents WHERE id = $1 AND clerk_org_id = $2)`,
+		incidentID, orgID,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrVersionConflict
+	}
+	return sql.ErrNoRows
+}
+
+// PatchIncident applies only the fields set in patch and the requested link
+// changes. When expectedVersion is non-nil the write is guarded the same way
+// as UpdateIncident. It returns the new version.
+func PatchIncident(ctx context.Context, db *sql.DB, orgID, incidentID string, patch Schemas.IncidentPatch, expectedVersion *int) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sets := []string{"updated_at = NOW()", "version = version + 1"}
+	var args []any
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if patch.Title != nil {
+		set("title", *patch.Title)
+	}
+	if patch.Description != nil {
+		set("description", *patch.Description)
+	}
+	if patch.Status != nil {
+		set("status", *patch.Status)
+		sets = append(sets, fmt.Sprintf(
+			"resolved_at = CASE WHEN $%d = 'resolved' THEN COALESCE(resolved_at, NOW()) END", len(args),
+		))
+	}
+	if patch.Severity != nil {
+		args = append(args, *patch.Severity)
+		sets = append(sets, fmt.Sprintf("severity = NULLIF($%d, '')", len(args)))
+	}
+	if patch.StartedAt != nil {
+		set("started_at", *patch.StartedAt)
+	}
+	if patch.Labels != nil {
+		add, remove := patch.Labels.Split()
+		args = append(args, add, pq.Array(remove))
+		sets = append(sets, fmt.Sprintf("labels = (labels || $%d::jsonb) - $%d::text[]", len(args)-1, len(args)))
+	}
+
+	args = append(args, incidentID, orgID)
+	where := fmt.Sprintf("id = $%d AND clerk_org_id = $%d", len(args)-1, len(args))
+	if expectedVersion != nil {
+		args = append(args, *expectedVersion)
+		where += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+
+	var version int
+	query := "UPDATE incidents SET " + strings.Join(sets, ", ") + " WHERE " + where + " RETURNING version"
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, incidentMissingOrStale(tx, incidentID, orgID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to patch incident: %w", err)
+	}
+
+	if patch.LinkedServices != nil {
+		if err := replaceIncidentLinks(ctx, tx, incidentID, *patch.LinkedServices); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(patch.RemoveLinkedServices) > 0 {
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM service_incidents si USING services s
+			WHERE si.incident_id = $1 AND s.id = si.service_id AND s.clerk_org_id = $3
+				AND (s.slug = ANY($2) OR s.id::text = ANY($2))
+		`, incidentID, pq.Array(patch.RemoveLinkedServices), orgID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to unlink services: %w", err)
+		}
+	}
+
+	for _, ref := range patch.AddLinkedServices {
+		// Only link services of the same org, and skip links that already exist.
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO service_incidents (service_id, incident_id, impact)
+			SELECT s.id, $2, $4 FROM services s
+			WHERE (s.slug = $1 OR s.id::text = $1) AND s.clerk_org_id = $3
+			AND NOT EXISTS (
+				SELECT 1 FROM service_incidents WHERE service_id = s.id AND incident_id = $2
+			)
+		`, ref, incidentID, orgID, Schemas.DefaultImpact)
+		if err != nil {
+			return 0, fmt.Errorf("failed to link service %s: %w", ref, err)
+		}
+	}
+
+	for ref, impact := range patch.ServiceImpacts {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE service_incidents si SET impact = $3 FROM services s
+			WHERE si.incident_id = $1 AND s.id = si.service_id AND s.clerk_org_id = $4
+				AND (s.slug = $2 OR s.id::text = $2)
+		`, incidentID, ref, impact, orgID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to set impact of service %s: %w", ref, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit incident patch: %w", err)
+	}
+	log.Printf("[PatchIncident] Patched incident ID: %s to version %d\n", incidentID, version)
+	return version, nil
+}
+
+func UpdateIncidentUpdate(db Executor, message, incidentId string, status Schemas.IncidentStatus, userId, fullName string) error {
+	return InsertTimelineEntry(db, message, incidentId, status, Schemas.VisibilityPublic, userId, fullName)
+}
+
+// InsertTimelineEntry writes a timeline entry with the given visibility.
+func InsertTimelineEntry(db Executor, message, incidentId string, status Schemas.IncidentStatus, visibility, userId, fullName string) error {
 	query := `
-		INSERT INTO incident_updates (incident_id, message, status, created_by_clerk, full_name)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO incident_updates (incident_id, message, status, created_by_clerk, full_name, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
-	_, err := db.Exec(query, incidentId, message, status, userId, fullName)
+	_, err := db.Exec(query, incidentId, message, status, userId, fullName, visibility)
 	if err != nil {
 		log.Printf("[UpdateIncident] Failed to insert update: %v\n", err)
 		return err
@@ -158,13 +370,39 @@ func UpdateIncidentUpdate(db *sql.DB, message, incidentId, status, userId, fullN
 	return nil
 }
 
-func GetIncidentUpdates(db *sql.DB, incidentId string) ([]Schemas.IncidentUpdateData, error) {
+// AddIncidentNote appends a free-text entry to the timeline of an incident in
+// the org, stamped with the incident's current status.
+func AddIncidentNote(db Executor, incidentID, orgID, message, visibility, userId, fullName string) (Schemas.IncidentUpdateData, error) {
+	var update Schemas.IncidentUpdateData
+
+	query := `
+		INSERT INTO incident_updates (incident_id, message, status, created_by_clerk, full_name, visibility)
+		SELECT id, $3, status, $4, $5, $6 FROM incidents WHERE id = $1 AND clerk_org_id = $2
+		RETURNING id, incident_id, message, status, created_at, full_name, created_by_clerk, visibility
+	`
+	err := db.QueryRow(query, incidentID, orgID, message, userId, fullName, visibility).Scan(
+		&update.ID,
+		&update.IncidentId,
+		&update.Message,
+		&update.Status,
+		&update.CreatedAt,
+		&update.FullName,
+		&update.CreatedByClerk,
+		&update.Visibility,
+	)
+	return update, err
+}
+
+// GetIncidentUpdates returns the incident's timeline oldest first. Internal
+// notes are only included when includeInternal is set, i.e. for org members.
+func GetIncidentUpdates(db *sql.DB, incidentId string, includeInternal bool) ([]Schemas.IncidentUpdateData, error) {
 	var incidentUpdates []Schemas.IncidentUpdateData
 
-	query := `SELECT id, incident_id, message, status, created_at, full_name, created_by_clerk
-	          FROM incident_updates WHERE incident_id = $1`
+	query := `SELECT id, incident_id, message, status, created_at, full_name, created_by_clerk, visibility
+	          FROM incident_updates WHERE incident_id = $1 AND (visibility = 'public' OR $2)
+	          ORDER BY created_at, id`
 
-	rows, err := db.Query(query, incidentId)
+	rows, err := db.Query(query, incidentId, includeInternal)
 	if err != nil {
 		return nil, err
 	}
@@ -180,6 +418,7 @@ func GetIncidentUpdates(db *sql.DB, incidentId string) ([]Schemas.IncidentUpdate
 			&update.CreatedAt,
 			&update.FullName,
 			&update.CreatedByClerk,
+			&update.Visibility,
 		)
 		if err != nil {
 			return nil, err
@@ -194,15 +433,144 @@ func GetIncidentUpdates(db *sql.DB, incidentId string) ([]Schemas.IncidentUpdate
 	return incidentUpdates, nil
 }
 
+// MergeIncidents folds the source incidents into the target: service links
+// are unioned, timeline entries are moved over with a note per source, and the
+// sources are resolved and left pointing at the target via merged_into.
+func MergeIncidents(ctx context.Context, db *sql.DB, orgID, targetID string, sourceIDs []string, userID, fullName string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var targetStatus Schemas.IncidentStatus
+	var targetMergedInto *string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, merged_into FROM incidents WHERE id = $1 AND clerk_org_id = $2 FOR UPDATE`,
+		targetID, orgID,
+	).Scan(&targetStatus, &targetMergedInto)
+	if err != nil {
+		return err
+	}
+	if targetMergedInto != nil {
+		return ErrAlreadyMerged
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, title, merged_into FROM incidents WHERE id = ANY($1) AND clerk_org_id = $2 FOR UPDATE`,
+		pq.Array(sourceIDs), orgID,
+	)
+	if err != nil {
+		return err
+	}
+	titles := map[string]string{}
+	for rows.Next() {
+		var id, title string
+		var mergedInto *string
+		if err := rows.Scan(&id, &title, &mergedInto); err != nil {
+			rows.Close()
+			return err
+		}
+		if mergedInto != nil {
+			rows.Close()
+			return ErrAlreadyMerged
+		}
+		titles[id] = title
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(titles) != len(sourceIDs) {
+		return sql.ErrNoRows
+	}
+
+	// Union the service links into the target, keeping the worst impact any
+	// source recorded for a service the target was not linked to yet.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO service_incidents (service_id, incident_id, impact)
+		SELECT service_id, $1::integer, ($3::text[])[MAX(array_position($3::text[], impact::text))]
+		FROM service_incidents
+		WHERE incident_id = ANY($2)
+		AND service_id NOT IN (SELECT service_id FROM service_incidents WHERE incident_id = $1)
+		GROUP BY service_id
+	`, targetID, pq.Array(sourceIDs), pq.Array(Schemas.ImpactLevels))
+	if err != nil {
+		return fmt.Errorf("failed to union service links: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM service_incidents WHERE incident_id = ANY($1)`, pq.Array(sourceIDs)); err != nil {
+		return fmt.Errorf("failed to clear source service links: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE incident_updates SET incident_id = $1 WHERE incident_id = ANY($2)`, targetID, pq.Array(sourceIDs)); err != nil {
+		return fmt.Errorf("failed to move incident updates: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE incident_attachments SET incident_id = $1 WHERE incident_id = ANY($2)`, targetID, pq.Array(sourceIDs)); err != nil {
+		return fmt.Errorf("failed to move attachments: %w", err)
+	}
+
+	for _, sourceID := range sourceIDs {
+		note, err := json.Marshal(Schemas.TimelineNote{
+			Note: fmt.Sprintf("Merged incident #%s (%s) into this incident", sourceID, titles[sourceID]),
+		})
+		if err != nil {
+			return err
+		}
+		if err := UpdateIncidentUpdate(tx, string(note), targetID, targetStatus, userID, fullName); err != nil {
+			return fmt.Errorf("failed to record merge of incident %s: %w", sourceID, err)
+		}
+	}
+
+	// Sources become redirects, as do incidents that were merged into them.
+	_, err = tx.ExecContext(ctx, `
+		UPDATE incidents
+		SET merged_into = $1, status = 'resolved', resolved_at = COALESCE(resolved_at, NOW()),
+			updated_at = NOW(), version = version + 1
+		WHERE id = ANY($2) OR merged_into = ANY($2)
+	`, targetID, pq.Array(sourceIDs))
+	if err != nil {
+		return fmt.Errorf("failed to mark merged incidents: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE incidents SET updated_at = NOW(), version = version + 1 WHERE id = $1`, targetID); err != nil {
+		return fmt.Errorf("failed to bump target incident: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteIncident removes an incident of the org together with its links,
+// timeline and attachment metadata. It returns sql.ErrNoRows when the
+// incident does not belong to the org.
 func DeleteIncident(db *sql.DB, incidentID string, orgId string) error {
-	_, err := db.Exec("DELETE FROM service_incidents WHERE incident_id = $1", incidentID)
+	tx, err := db.Begin()
 	if err != nil {
 		return err
 	}
-	_, err = db.Exec("DELETE FROM incident_updates WHERE incident_id = $1", incidentID)
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM incidents WHERE id = $1 AND clerk_org_id = $2)", incidentID, orgId,
+	).Scan(&exists)
 	if err != nil {
 		return err
 	}
-	_, err = db.Exec("DELETE FROM incidents WHERE id = $1 AND clerk_org_id = $2", incidentID, orgId)
-	return err
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	for _, query := range []string{
+		"DELETE FROM service_incidents WHERE incident_id = $1",
+		"DELETE FROM incident_attachments WHERE incident_id = $1",
+		"DELETE FROM incident_updates WHERE incident_id = $1",
+		"UPDATE incidents SET merged_into = NULL WHERE merged_into = $1",
+		"DELETE FROM incidents WHERE id = $1",
+	} {
+		if _, err := tx.Exec(query, incidentID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
@@ -0,0 +1,174 @@
+package dbrequests
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/lib/pq"
+)
+
+// CreateIngestKey stores the hash of token as a new ingest key of the org.
+func CreateIngestKey(db *sql.DB, orgID, name, token, clerkID string) (Schemas.IngestKey, error) {
+	key := Schemas.IngestKey{Name: name, Token: token}
+	err := db.QueryRow(`
+		INSERT INTO ingest_keys (clerk_org_id, name, token_hash, created_by_clerk)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, orgID, name, hashToken(token), clerkID).Scan(&key.ID, &key.CreatedAt)
+	return key, err
+}
+
+func GetIngestKeys(db *sql.DB, orgID string) ([]Schemas.IngestKey, error) {
+	rows, err := db.Query(`
+		SELECT id, name, created_at, last_used_at
+		FROM ingest_keys
+		WHERE clerk_org_id = $1
+		ORDER BY id
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []Schemas.IngestKey
+	for rows.Next() {
+		var k Schemas.IngestKey
+		if err := rows.Scan(&k.ID, &k.Name, &k.CreatedAt, &k.LastUsedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
+
+// DeleteIngestKey revokes a key. It returns sql.ErrNoRows when the org has no
+// such key.
+func DeleteIngestKey(db *sql.DB, keyID int, orgID string) error {
+	result, err := db.Exec(`DELETE FROM ingest_keys WHERE id = $1 AND clerk_org_id = $2`, keyID, orgID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UseIngestKey returns the org an ingest key belongs to and records that it
+// was used, or sql.ErrNoRows for an unknown key.
+func UseIngestKey(ctx context.Context, db *sql.DB, token string) (string, error) {
+	var orgID string
+	err := db.QueryRowContext(ctx, `
+		UPDATE ingest_keys SET last_used_at = NOW()
+		WHERE token_hash = $1
+		RETURNING clerk_org_id
+	`, hashToken(token)).Scan(&orgID)
+	return orgID, err
+}
+
+// RecordMetricPoints folds points into the minute, hour and day rollups of a
+// service. Buckets are aligned to UTC. A point is left out of the rollups it
+// is already too old for, e.g. a week-old point only updates hour and day.
+func RecordMetricPoints(ctx context.Context, db *sql.DB, serviceID int, points []Schemas.MetricPoint, now time.Time) error {
+	metrics := make([]string, len(points))
+	values := make([]float64, len(points))
+	stamps := make([]string, len(points))
+	for i, point := range points {
+		metrics[i] = point.Metric
+		values[i] = *point.Value
+		stamps[i] = point.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+	cutoffs := make([]string, len(Schemas.MetricResolutions))
+	for i, resolution := range Schemas.MetricResolutions {
+		cutoffs[i] = now.Add(-Schemas.MetricRetention[resolution]).UTC().Format(time.RFC3339Nano)
+	}
+
+	// Rows are written in key order so concurrent pushes lock them in the
+	// same order.
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO service_metric_rollups AS r (service_id, metric, resolution, bucket, count, sum, min, max)
+		SELECT $1, p.metric, res.resolution,
+			date_trunc(res.resolution, p.at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+			COUNT(*), SUM(p.value), MIN(p.value), MAX(p.value)
+		FROM unnest($2::text[], $3::float8[], $4::timestamptz[]) AS p(metric, value, at)
+		JOIN unnest($5::text[], $6::timestamptz[]) AS res(resolution, cutoff) ON p.at >= res.cutoff
+		GROUP BY p.metric, res.resolution, bucket
+		ORDER BY p.metric, res.resolution, bucket
+		ON CONFLICT (service_id, metric, resolution, bucket) DO UPDATE
+		SET count = r.count + EXCLUDED.count, sum = r.sum + EXCLUDED.sum,
+			min = LEAST(r.min, EXCLUDED.min), max = GREATEST(r.max, EXCLUDED.max)
+	`, serviceID, pq.Array(metrics), pq.Array(values), pq.Array(stamps), pq.Array(Schemas.MetricResolutions), pq.Array(cutoffs))
+	return err
+}
+
+// GetMetricBuckets returns the rollups of a metric whose bucket starts in
+// [from, to), oldest first.
+func GetMetricBuckets(db *sql.DB, serviceID int, metric, resolution string, from, to time.Time) ([]Schemas.MetricBucket, error) {
+	rows, err := db.Query(`
+		SELECT bucket, count, sum, min, max
+		FROM service_metric_rollups
+		WHERE service_id = $1 AND metric = $2 AND resolution = $3 AND bucket >= $4 AND bucket < $5
+		ORDER BY bucket
+	`, serviceID, metric, resolution, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []Schemas.MetricBucket
+	for rows.Next() {
+		var b Schemas.MetricBucket
+		if err := rows.Scan(&b.Bucket, &b.Count, &b.Sum, &b.Min, &b.Max); err != nil {
+			return nil, err
+		}
+		if b.Count > 0 {
+			b.Avg = b.Sum / float64(b.Count)
+		}
+		b.Bucket = b.Bucket.UTC()
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
+}
+
+// GetMetricNames lists the metrics a service has data for. Day rollups are
+// kept longest, so they cover every metric still stored.
+func GetMetricNames(db *sql.DB, serviceID int) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT DISTINCT metric FROM service_metric_rollups
+		WHERE service_id = $1 AND resolution = 'day'
+		ORDER BY metric
+	`, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// PruneMetricRollups deletes rollups older than their resolution's retention.
+func PruneMetricRollups(ctx context.Context, db *sql.DB, now time.Time) error {
+	for _, resolution := range Schemas.MetricResolutions {
+		_, err := db.ExecContext(ctx,
+			`DELETE FROM service_metric_rollups WHERE resolution = $1 AND bucket < $2`,
+			resolution, now.Add(-Schemas.MetricRetention[resolution]),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package dbrequests
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// monitorColumns is the column list scanMonitor expects; queries alias
+// monitors as m and join services as s.
+const monitorColumns = `m.id, m.service_id, m.kind, m.target, m.expected_status, m.body_contains,
+	m.interval_seconds, m.timeout_seconds, m.failure_threshold, m.failure_status, m.open_incident,
+	m.enabled, m.consecutive_failures, m.tripped, m.incident_id, m.last_checked_at, s.clerk_org_id, s.name`
+
+func scanMonitor(row rowScanner) (Schemas.Monitor, error) {
+	var m Schemas.Monitor
+	err := row.Scan(
+		&m.ID, &m.ServiceID, &m.Kind, &m.Target, &m.ExpectedStatus, &m.BodyContains,
+		&m.IntervalSeconds, &m.TimeoutSeconds, &m.FailureThreshold, &m.FailureStatus, &m.OpenIncident,
+		&m.Enabled, &m.ConsecutiveFailures, &m.Tripped, &m.IncidentID, &m.LastCheckedAt, &m.OrgID, &m.ServiceName,
+	)
+	return m, err
+}
+
+func scanMonitors(rows *sql.Rows) ([]Schemas.Monitor, error) {
+	defer rows.Close()
+
+	var monitors []Schemas.Monitor
+	for rows.Next() {
+		m, err := scanMonitor(rows)
+		if err != nil {
+			return nil, err
+		}
+		monitors = append(monitors, m)
+	}
+	return monitors, rows.Err()
+}
+
+// CreateMonitor stores a normalized request. The caller checks that the
+// service belongs to the org.
+func CreateMonitor(db *sql.DB, request Schemas.MonitorRequest) (Schemas.Monitor, error) {
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO monitors (service_id, kind, target, expected_status, body_contains, interval_seconds,
+			timeout_seconds, failure_threshold, failure_status, open_incident, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, request.ServiceID, request.Kind, request.Target, request.ExpectedStatus, request.BodyContains, request.IntervalSeconds,
+		request.TimeoutSeconds, request.FailureThreshold, request.FailureStatus, request.OpenIncident, *request.Enabled,
+	).Scan(&id)
+	if err != nil {
+		return Schemas.Monitor{}, err
+	}
+
+	return scanMonitor(db.QueryRow(`SELECT `+monitorColumns+` FROM monitors m JOIN services s ON s.id = m.service_id WHERE m.id = $1`, id))
+}
+
+// GetMonitorsForOrg lists the org's monitors, only those of serviceID when it
+// is non-zero.
+func GetMonitorsForOrg(db *sql.DB, orgID string, serviceID int) ([]Schemas.Monitor, error) {
+	rows, err := db.Query(`
+		SELECT `+monitorColumns+`
+		FROM monitors m JOIN services s ON s.id = m.service_id
+		WHERE s.clerk_org_id = $1 AND ($2 = 0 OR m.service_id = $2)
+		ORDER BY m.service_id, m.id
+	`, orgID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return scanMonitors(rows)
+}
+
+func GetMonitor(db *sql.DB, monitorID int, orgID string) (Schemas.Monitor, error) {
+	return scanMonitor(db.QueryRow(`
+		SELECT `+monitorColumns+`
+		FROM monitors m JOIN services s ON s.id = m.service_id
+		WHERE m.id = $1 AND s.clerk_org_id = $2
+	`, monitorID, orgID))
+}
+
+// UpdateMonitor replaces a monitor's configuration, keeping its state. A
+// tripped monitor that is disabled, moved to another service or given another
+// failure status lets go of its service: its state is cleared and it is returned as it was before, so
+// the caller can resolve its incident and restore the service. It returns
+// sql.ErrNoRows when the org has no such monitor.
+func UpdateMonitor(db *sql.DB, monitorID int, orgID string, request Schemas.MonitorRequest) (Schemas.Monitor, *Schemas.Monitor, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return Schemas.Monitor{}, nil, err
+	}
+	defer tx.Rollback()
+
+	previous, err := lockMonitor(tx, monitorID, orgID)
+	if err != nil {
+		return Schemas.Monitor{}, nil, err
+	}
+
+	released := previous.Tripped && (!*request.Enabled || request.ServiceID != previous.ServiceID || request.FailureStatus != previous.FailureStatus)
+	_, err = tx.Exec(`
+		UPDATE monitors
+		SET service_id = $1, kind = $2, target = $3, expected_status = $4, body_contains = $5,
+			interval_seconds = $6, timeout_seconds = $7, failure_threshold = $8, failure_status = $9,
+			open_incident = $10, enabled = $11, updated_at = NOW(),
+			tripped = tripped AND NOT $13,
+			incident_id = CASE WHEN $13 THEN NULL ELSE incident_id END,
+			consecutive_failures = CASE WHEN $13 THEN 0 ELSE consecutive_failures END
+		WHERE id = $12
+	`, request.ServiceID, request.Kind, request.Target, request.ExpectedStatus, request.BodyContains,
+		request.IntervalSeconds, request.TimeoutSeconds, request.FailureThreshold, request.FailureStatus,
+		request.OpenIncident, *request.Enabled, monitorID, released)
+	if err != nil {
+		return Schemas.Monitor{}, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Schemas.Monitor{}, nil, err
+	}
+
+	monitor, err := GetMonitor(db, monitorID, orgID)
+	if err != nil || !released {
+		return monitor, nil, err
+	}
+	return monitor, &previous, nil
+}
+
+// DeleteMonitor removes a monitor and its results and returns it as it was,
+// so the caller can let go of its service if it was tripped. It returns
+// sql.ErrNoRows when the org has no such monitor.
+func DeleteMonitor(db *sql.DB, monitorID int, orgID string) (Schemas.Monitor, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return Schemas.Monitor{}, err
+	}
+	defer tx.Rollback()
+
+	monitor, err := lockMonitor(tx, monitorID, orgID)
+	if err != nil {
+		return Schemas.Monitor{}, err
+	}
+	if _, err := tx.Exec(`DELETE FROM monitors WHERE id = $1`, monitorID); err != nil {
+		return Schemas.Monitor{}, err
+	}
+
+	return monitor, tx.Commit()
+}
+
+// lockMonitor loads the org's monitor and locks it for the rest of tx, so a
+// running check cannot trip it meanwhile.
+func lockMonitor(tx *sql.Tx, monitorID int, orgID string) (Schemas.Monitor, error) {
+	return scanMonitor(tx.QueryRow(`
+		SELECT `+monitorColumns+`
+		FROM monitors m JOIN services s ON s.id = m.service_id
+		WHERE m.id = $1 AND s.clerk_org_id = $2
+		FOR UPDATE OF m
+	`, monitorID, orgID))
+}
+
+// ClaimDueMonitors stamps and returns the enabled monitors whose interval has
+// elapsed. Rows locked by another server instance are skipped, so each check
+// runs once.
+func ClaimDueMonitors(ctx context.Context, db *sql.DB) ([]Schemas.Monitor, error) {
+	rows, err := db.QueryContext(ctx, `
+		WITH due AS (
+			SELECT id FROM monitors
+			WHERE enabled AND (last_checked_at IS NULL
+				OR last_checked_at + interval_seconds * interval '1 second' <= NOW())
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE monitors m
+		SET last_checked_at = NOW()
+		FROM due, services s
+		WHERE m.id = due.id AND s.id = m.service_id
+		RETURNING `+monitorColumns)
+	if err != nil {
+		return nil, err
+	}
+	return scanMonitors(rows)
+}
+
+// RecordMonitorResult stores a check result, updates the monitor's failure
+// streak and returns the monitor as it is afterwards.
+func RecordMonitorResult(ctx context.Context, db *sql.DB, result Schemas.MonitorResult) (Schemas.Monitor, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Schemas.Monitor{}, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO monitor_results (monitor_id, checked_at, success, latency_ms, error)
+		VALUES ($1, $2, $3, $4, $5)
+	`, result.MonitorID, result.CheckedAt, result.Success, result.LatencyMs, result.Error)
+	if err != nil {
+		return Schemas.Monitor{}, err
+	}
+
+	monitor, err := scanMonitor(tx.QueryRowContext(ctx, `
+		UPDATE monitors m
+		SET consecutive_failures = CASE WHEN $2 THEN 0 ELSE m.consecutive_failures + 1 END
+		FROM services s
+		WHERE s.id = m.service_id AND m.id = $1
+		RETURNING `+monitorColumns, result.MonitorID, result.Success))
+	if err != nil {
+		return Schemas.Monitor{}, err
+	}
+
+	return monitor, tx.Commit()
+}
+
+// SetMonitorTripped records whether the monitor currently holds its service
+// down and which incident it opened for it, if any.
+func SetMonitorTripped(ctx context.Context, db *sql.DB, monitorID int, tripped bool, incidentID *string) error {
+	_, err := db.ExecContext(ctx,
+		`UPDATE monitors SET tripped = $2, incident_id = $3 WHERE id = $1`,
+		monitorID, tripped, incidentID,
+	)
+	return err
+}
+
+// GetMonitorResults returns the monitor's latest results, newest first.
+func GetMonitorResults(db *sql.DB, monitorID, limit int) ([]Schemas.MonitorResult, error) {
+	rows, err := db.Query(`
+		SELECT id, monitor_id, checked_at, success, latency_ms, error
+		FROM monitor_results
+		WHERE monitor_id = $1
+		ORDER BY checked_at DESC, id DESC
+		LIMIT $2
+	`, monitorID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []Schemas.MonitorResult
+	for rows.Next() {
+		var r Schemas.MonitorResult
+		if err := rows.Scan(&r.ID, &r.MonitorID, &r.CheckedAt, &r.Success, &r.LatencyMs, &r.Error); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// PruneMonitorResults deletes results checked before the cutoff.
+func PruneMonitorResults(ctx context.Context, db *sql.DB, before time.Time) error {
+	_, err := db.ExecContext(ctx, `DELETE FROM monitor_results WHERE checked_at < $1`, before)
+	return err
+}
+
+// OtherTrippedChecks reports whether a monitor or heartbeat other than the
+// given ones (0 for none) currently holds the service down. Disabled monitors
+// do not count.
+func OtherTrippedChecks(ctx context.Context, db *sql.DB, serviceID, monitorID, heartbeatID int) (bool, error) {
+	var tripped bool
+	err := db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM monitors WHERE service_id = $1 AND id <> $2 AND enabled AND tripped)
+			OR EXISTS(SELECT 1 FROM heartbeats WHERE service_id = $1 AND id <> $3 AND tripped)
+	`, serviceID, monitorID, heartbeatID).Scan(&tripped)
+	return tripped, err
+}
@@ -0,0 +1,227 @@
+package dbrequests
+
+import (
+	"database/sql"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/lib/pq"
+)
+
+// SyncOrganization records the slug Clerk currently reports for an org. A
+// slug given up by another org is taken over.
+func SyncOrganization(db *sql.DB, orgID, slug string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM organizations WHERE slug = $2 AND clerk_org_id <> $1`, orgID, slug); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO organizations (clerk_org_id, slug) VALUES ($1, $2)
+		ON CONFLICT (clerk_org_id) DO UPDATE SET slug = EXCLUDED.slug, updated_at = NOW()
+		WHERE organizations.slug <> EXCLUDED.slug
+	`, orgID, slug)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetStatusPageSettings returns sql.ErrNoRows until the org has been seen
+// with a slug.
+func GetStatusPageSettings(db *sql.DB, orgID string) (Schemas.StatusPageSettings, error) {
+	var settings Schemas.StatusPageSettings
+	err := db.QueryRow(
+		`SELECT slug, public_page FROM organizations WHERE clerk_org_id = $1`, orgID,
+	).Scan(&settings.Slug, &settings.Enabled)
+	return settings, err
+}
+
+// SetStatusPageEnabled turns the public page on or off. It returns
+// sql.ErrNoRows until the org has been seen with a slug.
+func SetStatusPageEnabled(db *sql.DB, orgID string, enabled bool) (Schemas.StatusPageSettings, error) {
+	var settings Schemas.StatusPageSettings
+	err := db.QueryRow(`
+		UPDATE organizations SET public_page = $2, updated_at = NOW()
+		WHERE clerk_org_id = $1
+		RETURNING slug, public_page
+	`, orgID, enabled).Scan(&settings.Slug, &settings.Enabled)
+	return settings, err
+}
+
+// PublicOrgID returns the org whose public page is served under slug, or
+// sql.ErrNoRows when there is none or it is not enabled.
+func PublicOrgID(db *sql.DB, slug string) (string, error) {
+	var orgID string
+	err := db.QueryRow(
+		`SELECT clerk_org_id FROM organizations WHERE slug = $1 AND public_page`, slug,
+	).Scan(&orgID)
+	return orgID, err
+}
+
+// GetPublicServices lists the org's services that are not hidden, in display
+// order.
+func GetPublicServices(db *sql.DB, orgID string) ([]Schemas.PublicService, error) {
+	rows, err := db.Query(`
+		SELECT id, slug, name, status, description, public_url, group_id, created_at, updated_at
+		FROM services
+		WHERE clerk_org_id = $1 AND NOT hidden
+		ORDER BY display_order, id
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	services := []Schemas.PublicService{}
+	for rows.Next() {
+		var s Schemas.PublicService
+		if err := rows.Scan(&s.ID, &s.Slug, &s.Name, &s.Status, &s.Description, &s.PublicURL, &s.GroupID, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		services = append(services, s)
+	}
+
+	return services, rows.Err()
+}
+
+// GetPublicIncidents lists the org's unresolved incidents, newest first, with
+// their visible affected services and public updates.
+func GetPublicIncidents(db *sql.DB, orgID string) ([]Schemas.PublicIncident, error) {
+	return publicIncidents(db, `
+		WHERE i.clerk_org_id = $1 AND i.status <> 'resolved' AND i.merged_into IS NULL
+		ORDER BY i.started_at DESC, i.id DESC
+	`, orgID)
+}
+
+// GetPublicIncidentHistory lists the org's latest incidents, resolved ones
+// included, most recently changed first: an incident changes when it is
+// edited or gets a public update. A non-zero serviceID keeps only the
+// incidents linked to that service.
+func GetPublicIncidentHistory(db *sql.DB, orgID string, serviceID, limit int) ([]Schemas.PublicIncident, error) {
+	return publicIncidents(db, `
+		WHERE i.clerk_org_id = $1 AND i.merged_into IS NULL
+			AND ($2 = 0 OR EXISTS (SELECT 1 FROM service_incidents si WHERE si.incident_id = i.id AND si.service_id = $2))
+		ORDER BY GREATEST(i.updated_at, (
+			SELECT MAX(u.created_at) FROM incident_updates u WHERE u.incident_id = i.id AND u.visibility = 'public'
+		)) DESC, i.id DESC
+		LIMIT $3
+	`, orgID, serviceID, limit)
+}
+
+// publicIncidents loads the incidents selected by the rest of a query over
+// incidents i. Incidents whose linked services are all hidden are left out;
+// incidents without links are kept. Updates are newest first.
+func publicIncidents(db *sql.DB, rest string, args ...any) ([]Schemas.PublicIncident, error) {
+	rows, err := db.Query(`
+		SELECT i.id, i.title, COALESCE(i.description, ''), i.status, i.started_at, i.updated_at, i.resolved_at,
+			(SELECT COUNT(*) FROM service_incidents si WHERE si.incident_id = i.id)
+		FROM incidents i
+	`+rest, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []Schemas.PublicIncident
+	links := map[string]int{}
+	var ids []string
+	for rows.Next() {
+		var i Schemas.PublicIncident
+		var linked int
+		if err := rows.Scan(&i.ID, &i.Title, &i.Description, &i.Status, &i.StartedAt, &i.UpdatedAt, &i.ResolvedAt, &linked); err != nil {
+			return nil, err
+		}
+		i.AffectedServices = []Schemas.PublicAffectedService{}
+		i.Updates = []Schemas.PublicUpdate{}
+		incidents = append(incidents, i)
+		links[i.ID] = linked
+		ids = append(ids, i.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(incidents) == 0 {
+		return []Schemas.PublicIncident{}, nil
+	}
+
+	affected, err := publicAffectedServices(db, ids)
+	if err != nil {
+		return nil, err
+	}
+	updates, err := publicUpdates(db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []Schemas.PublicIncident{}
+	for _, i := range incidents {
+		if links[i.ID] > 0 && len(affected[i.ID]) == 0 {
+			continue
+		}
+		if affected[i.ID] != nil {
+			i.AffectedServices = affected[i.ID]
+		}
+		if updates[i.ID] != nil {
+			i.Updates = updates[i.ID]
+		}
+		visible = append(visible, i)
+	}
+	return visible, nil
+}
+
+func publicAffectedServices(db *sql.DB, incidentIDs []string) (map[string][]Schemas.PublicAffectedService, error) {
+	rows, err := db.Query(`
+		SELECT si.incident_id, s.id, s.slug, s.name, si.impact
+		FROM service_incidents si
+		JOIN services s ON s.id = si.service_id
+		WHERE si.incident_id = ANY($1::int[]) AND NOT s.hidden
+		ORDER BY s.display_order, s.id
+	`, pq.Array(incidentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	affected := map[string][]Schemas.PublicAffectedService{}
+	for rows.Next() {
+		var incidentID string
+		var s Schemas.PublicAffectedService
+		if err := rows.Scan(&incidentID, &s.ID, &s.Slug, &s.Name, &s.Impact); err != nil {
+			return nil, err
+		}
+		affected[incidentID] = append(affected[incidentID], s)
+	}
+
+	return affected, rows.Err()
+}
+
+func publicUpdates(db *sql.DB, incidentIDs []string) (map[string][]Schemas.PublicUpdate, error) {
+	rows, err := db.Query(`
+		SELECT id, incident_id, message, status, created_at
+		FROM incident_updates
+		WHERE incident_id = ANY($1::int[]) AND visibility = 'public'
+		ORDER BY created_at DESC, id DESC
+	`, pq.Array(incidentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updates := map[string][]Schemas.PublicUpdate{}
+	for rows.Next() {
+		var incidentID, message string
+		var u Schemas.PublicUpdate
+		if err := rows.Scan(&u.ID, &incidentID, &message, &u.Status, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		u.Body = Schemas.UpdateText(message)
+		updates[incidentID] = append(updates[incidentID], u)
+	}
+
+	return updates, rows.Err()
+}
@@ -0,0 +1,84 @@
+package dbrequests
+
+import (
+	"database/sql"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/lib/pq"
+)
+
+// incidentTimingsQuery derives each incident's milestones from the statuses
+// recorded in its timeline. The caller appends the WHERE clause.
+const incidentTimingsQuery = `
+	SELECT i.id, COALESCE(i.severity, ''), i.started_at,
+		(SELECT MIN(u.created_at) FROM incident_updates u
+			WHERE u.incident_id = i.id AND u.status IN ('identified', 'monitoring', 'resolved')),
+		(SELECT MIN(u.created_at) FROM incident_updates u
+			WHERE u.incident_id = i.id AND u.status IN ('monitoring', 'resolved')),
+		COALESCE(i.resolved_at, (SELECT MIN(u.created_at) FROM incident_updates u
+			WHERE u.incident_id = i.id AND u.status = 'resolved')),
+		ARRAY(SELECT si.service_id FROM service_incidents si WHERE si.incident_id = i.id ORDER BY si.service_id)
+	FROM incidents i
+`
+
+func scanIncidentTimings(row rowScanner) (Schemas.IncidentTimings, error) {
+	var t Schemas.IncidentTimings
+	err := row.Scan(
+		&t.IncidentID,
+		&t.Severity,
+		&t.StartedAt,
+		&t.AcknowledgedAt,
+		&t.MitigatedAt,
+		&t.ResolvedAt,
+		pq.Array(&t.ServiceIDs),
+	)
+	if err != nil {
+		return t, err
+	}
+
+	t.TimeToAcknowledgeSeconds = secondsSince(t.StartedAt, t.AcknowledgedAt)
+	t.TimeToMitigateSeconds = secondsSince(t.StartedAt, t.MitigatedAt)
+	t.TimeToResolveSeconds = secondsSince(t.StartedAt, t.ResolvedAt)
+	return t, nil
+}
+
+// secondsSince returns the seconds from start to end, clamped at zero for
+// incidents whose started_at was backdated past their first updates.
+func secondsSince(start time.Time, end *time.Time) *float64 {
+	if end == nil {
+		return nil
+	}
+	seconds := max(end.Sub(start).Seconds(), 0)
+	return &seconds
+}
+
+func GetIncidentTimings(db *sql.DB, incidentID, orgID string) (Schemas.IncidentTimings, error) {
+	row := db.QueryRow(incidentTimingsQuery+` WHERE i.id = $1 AND i.clerk_org_id = $2`, incidentID, orgID)
+	return scanIncidentTimings(row)
+}
+
+// GetIncidentTimingsForOrg returns timings for the org's incidents that
+// started in [from, to), leaving out incidents merged into another one.
+func GetIncidentTimingsForOrg(db *sql.DB, orgID string, from, to time.Time) ([]Schemas.IncidentTimings, error) {
+	rows, err := db.Query(incidentTimingsQuery+`
+		WHERE i.clerk_org_id = $1 AND i.merged_into IS NULL
+		AND i.started_at >= $2 AND i.started_at < $3
+		ORDER BY i.started_at
+	`, orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var timings []Schemas.IncidentTimings
+	for rows.Next() {
+		t, err := scanIncidentTimings(rows)
+		if err != nil {
+			return nil, err
+		}
+		timings = append(timings, t)
+	}
+
+	return timings, rows.Err()
+}
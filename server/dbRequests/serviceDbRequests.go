@@ -1,83 +1,132 @@
 package dbrequests
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	Schemas "github.com/krnveersharma/Statuses/schemas"
 	"github.com/lib/pq"
 )
 
+// serviceColumns is the column list scanService expects, in order. Columns are
+// qualified so queries can join other tables.
+const serviceColumns = "services.id, services.name, services.slug, services.status, services.version, services.labels, services.status_mode, services.group_id, " +
+	"services.description, services.public_url, services.runbook_url, services.owner_team, services.contact, services.display_order, services.hidden"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanService scans serviceColumns into service, followed by any extra
+// columns the query selects after them.
+func scanService(row rowScanner, service *Schemas.Service, extra ...any) error {
+	dest := []any{&service.ID, &service.Name, &service.Slug, &service.Status, &service.Version, &service.Labels, &service.StatusMode, &service.GroupID,
+		&service.Description, &service.PublicURL, &service.RunbookURL, &service.OwnerTeam, &service.Contact, &service.DisplayOrder, &service.Hidden}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// AddService stores a new service. The caller derives the slug from the name
+// when none was given; a slug already in use gives ErrSlugTaken.
 func AddService(db *sql.DB, serviceData Schemas.ServiceRequest, orgId, clerkId string) (Schemas.Service, error) {
 	var service Schemas.Service
 
+	tx, err := db.Begin()
+	if err != nil {
+		return service, err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO services (name, status, clerk_org_id, created_by_clerk)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, name, status
-	`
+		INSERT INTO services (name, status, clerk_org_id, created_by_clerk, labels, status_mode, group_id,
+			description, public_url, runbook_url, owner_team, contact, display_order, hidden, slug)
+		VALUES ($1, $2, $3, $4, COALESCE($5, '{}'::jsonb), COALESCE(NULLIF($6, ''), 'manual'), NULLIF($7, 0),
+			COALESCE($8, ''), COALESCE($9, ''), COALESCE($10, ''), COALESCE($11, ''), COALESCE($12, ''), COALESCE($13, 0), COALESCE($14, false), $15)
+		RETURNING ` + serviceColumns
 
-	err := db.QueryRow(query, serviceData.Name, serviceData.Status, orgId, clerkId).
-		Scan(&service.ID, &service.Name, &service.Status)
+	err = scanService(tx.QueryRow(query,
+		serviceData.Name, serviceData.Status, orgId, clerkId, serviceData.Labels, serviceData.StatusMode, serviceData.GroupID,
+		serviceData.Description, serviceData.PublicURL, serviceData.RunbookURL, serviceData.OwnerTeam, serviceData.Contact, serviceData.DisplayOrder, serviceData.Hidden,
+		serviceData.Slug,
+	), &service)
 
 	if err != nil {
+		return Schemas.Service{}, slugConflict(err)
+	}
+	if err := recordServiceStatus(tx, service.ID, service.Status); err != nil {
 		return Schemas.Service{}, err
 	}
 
-	return service, nil
+	return service, tx.Commit()
 }
 
+// GetServicesAffected lists the services linked to an incident along with the
+// incident's impact on each.
 func GetServicesAffected(db *sql.DB, incidentId string) ([]Schemas.Service, error) {
 	var services []Schemas.Service
 
-	// First, get all service_ids from service_incidents table
-	rows, err := db.Query("SELECT service_id FROM service_incidents WHERE incident_id = $1", incidentId)
+	query := `
+		SELECT ` + serviceColumns + `, si.impact
+		FROM service_incidents si
+		JOIN services ON services.id = si.service_id
+		WHERE si.incident_id = $1
+		ORDER BY services.id
+	`
+	rows, err := db.Query(query, incidentId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var serviceIDs []string
 	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err != nil {
+		var s Schemas.Service
+		if err := scanService(rows, &s, &s.Impact); err != nil {
 			return nil, err
 		}
-		serviceIDs = append(serviceIDs, id)
+		services = append(services, s)
 	}
 
-	if len(serviceIDs) == 0 {
-		return services, nil // No linked services
+	return services, rows.Err()
+}
+
+// GetServicesForOrg lists the org's services, keeping only those carrying all
+// of the given labels when labels is non-empty.
+func GetServicesForOrg(db *sql.DB, orgId string, labels Schemas.Labels) ([]Schemas.Service, error) {
+	query := "SELECT " + serviceColumns + " FROM services WHERE clerk_org_id = $1"
+	args := []any{orgId}
+	if len(labels) > 0 {
+		query += " AND labels @> $2"
+		args = append(args, labels)
 	}
 
-	// Now fetch full service details
-	query := `
-		SELECT id, name, status
-		FROM services
-		WHERE id = ANY($1)
-	`
-	rows2, err := db.Query(query, pq.Array(serviceIDs))
+	rows, err := db.Query(query+" ORDER BY display_order, id", args...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows2.Close()
+	defer rows.Close()
 
-	for rows2.Next() {
+	var services []Schemas.Service
+	for rows.Next() {
 		var s Schemas.Service
-		if err := rows2.Scan(&s.ID, &s.Name, &s.Status); err != nil {
+		if err := scanService(rows, &s); err != nil {
 			return nil, err
 		}
 		services = append(services, s)
 	}
 
-	return services, nil
+	return services, rows.Err()
 }
 
 func GetServiceByID(db *sql.DB, serviceID, orgId string) (Schemas.ServiceResponse, error) {
 	var service Schemas.ServiceResponse
 
-	query := `SELECT id, name, status, created_at FROM services WHERE id = $1 AND clerk_org_id = $2 LIMIT 1`
+	query := `SELECT ` + serviceColumns + `, created_at FROM services WHERE id = $1 AND clerk_org_id = $2 LIMIT 1`
 
-	err := db.QueryRow(query, serviceID, orgId).Scan(&service.ID, &service.Name, &service.Status, &service.CreatedAt)
+	err := scanService(db.QueryRow(query, serviceID, orgId), &service.Service, &service.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return service, nil
@@ -88,26 +137,363 @@ func GetServiceByID(db *sql.DB, serviceID, orgId string) (Schemas.ServiceRespons
 	return service, nil
 }
 
-func EditService(db *sql.DB, service Schemas.Service, orgId string) error {
+// EditService updates the service only if its stored version still equals
+// expectedVersion and returns the new version. Nil labels, group and metadata
+// fields and an empty status mode keep the stored ones; group 0 ungroups the
+// service. It returns ErrVersionConflict when the row changed meanwhile and
+// sql.ErrNoRows when it does not exist.
+func EditService(db *sql.DB, service Schemas.Service, orgId string, expectedVersion int) (int, error) {
 	query := `
 		UPDATE services
-		SET name = $1, status = $2
-		WHERE id = $3 AND clerk_org_id = $4
+		SET name = $1, status = $2, labels = COALESCE($6, labels),
+			status_mode = COALESCE(NULLIF($7, ''), status_mode), updated_at = NOW(), version = version + 1,
+			group_id = CASE WHEN $8::int IS NULL THEN group_id ELSE NULLIF($8, 0) END,
+			description = COALESCE($9, description), public_url = COALESCE($10, public_url),
+			runbook_url = COALESCE($11, runbook_url), owner_team = COALESCE($12, owner_team),
+			contact = COALESCE($13, contact), display_order = COALESCE($14, display_order), hidden = COALESCE($15, hidden),
+			slug = COALESCE(NULLIF($16, ''), slug)
+		WHERE id = $3 AND clerk_org_id = $4 AND version = $5
+		RETURNING version
 	`
 
-	_, err := db.Exec(query, service.Name, service.Status, service.ID, orgId)
+	tx, err := db.Begin()
 	if err != nil {
-		return err
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var version int
+	err = tx.QueryRow(query,
+		service.Name, service.Status, service.ID, orgId, expectedVersion, service.Labels, service.StatusMode, service.GroupID,
+		service.Description, service.PublicURL, service.RunbookURL, service.OwnerTeam, service.Contact, service.DisplayOrder, service.Hidden,
+		service.Slug,
+	).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, serviceMissingOrStale(tx, service.ID, orgId)
+	}
+	if err != nil {
+		return 0, slugConflict(err)
+	}
+	if err := recordServiceStatus(tx, service.ID, service.Status); err != nil {
+		return 0, err
+	}
+
+	return version, tx.Commit()
+}
+
+// PatchService applies only the fields set in patch and returns the updated
+// service. When expectedVersion is non-nil the write is guarded the same way
+// as EditService.
+func PatchService(db *sql.DB, serviceID int, orgId string, patch Schemas.ServicePatch, expectedVersion *int) (Schemas.Service, error) {
+	var service Schemas.Service
+
+	sets := []string{"updated_at = NOW()", "version = version + 1"}
+	var args []any
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if patch.Name != nil {
+		set("name", *patch.Name)
+	}
+	if patch.Slug != nil {
+		set("slug", *patch.Slug)
+	}
+	if patch.Status != nil {
+		set("status", *patch.Status)
+	}
+	if patch.StatusMode != nil {
+		set("status_mode", *patch.StatusMode)
+	}
+	if patch.GroupID != nil {
+		args = append(args, *patch.GroupID)
+		sets = append(sets, fmt.Sprintf("group_id = NULLIF($%d, 0)", len(args)))
+	}
+	if patch.Description != nil {
+		set("description", *patch.Description)
+	}
+	if patch.PublicURL != nil {
+		set("public_url", *patch.PublicURL)
+	}
+	if patch.RunbookURL != nil {
+		set("runbook_url", *patch.RunbookURL)
+	}
+	if patch.OwnerTeam != nil {
+		set("owner_team", *patch.OwnerTeam)
+	}
+	if patch.Contact != nil {
+		set("contact", *patch.Contact)
+	}
+	if patch.DisplayOrder != nil {
+		set("display_order", *patch.DisplayOrder)
+	}
+	if patch.Hidden != nil {
+		set("hidden", *patch.Hidden)
+	}
+	if patch.Labels != nil {
+		add, remove := patch.Labels.Split()
+		args = append(args, add, pq.Array(remove))
+		sets = append(sets, fmt.Sprintf("labels = (labels || $%d::jsonb) - $%d::text[]", len(args)-1, len(args)))
+	}
+
+	args = append(args, serviceID, orgId)
+	where := fmt.Sprintf("id = $%d AND clerk_org_id = $%d", len(args)-1, len(args))
+	if expectedVersion != nil {
+		args = append(args, *expectedVersion)
+		where += fmt.Sprintf(" AND version = $%d", len(args))
 	}
 
-	return nil
+	tx, err := db.Begin()
+	if err != nil {
+		return service, err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE services SET " + strings.Join(sets, ", ") + " WHERE " + where + " RETURNING " + serviceColumns
+	err = scanService(tx.QueryRow(query, args...), &service)
+	if err == sql.ErrNoRows {
+		return service, serviceMissingOrStale(tx, serviceID, orgId)
+	}
+	if err != nil {
+		return service, slugConflict(err)
+	}
+	if err := recordServiceStatus(tx, service.ID, service.Status); err != nil {
+		return service, err
+	}
+
+	return service, tx.Commit()
 }
 
-func DeleteService(db *sql.DB, serviceID int, orgId string) error {
-	_, err := db.Exec("DELETE FROM service_incidents WHERE service_id = $1", serviceID)
+// RecomputeAutoStatuses sets every auto-mode service of the org to the worst
+// impact among its linked unresolved incidents, or operational when there are
+// none. Maintenance counts as under maintenance, whatever the link's impact,
+// and only once it has started. It returns the services whose status
+// changed.
+func RecomputeAutoStatuses(db *sql.DB, orgId string) ([]Schemas.Service, error) {
+	query := `
+		UPDATE services
+		SET status = computed.status::service_status, updated_at = NOW(), version = services.version + 1
+		FROM (
+			SELECT s.id, COALESCE(($2::text[])[MAX(array_position($2::text[], active.level))], 'operational') AS status
+			FROM services s
+			LEFT JOIN (
+				SELECT si.service_id,
+					CASE WHEN i.status = 'maintenance' THEN $3 ELSE si.impact::text END AS level
+				FROM service_incidents si
+				JOIN incidents i ON i.id = si.incident_id
+				WHERE i.status <> 'resolved' AND i.merged_into IS NULL
+					AND (i.status <> 'maintenance' OR i.started_at <= NOW())
+			) active ON active.service_id = s.id
+			WHERE s.clerk_org_id = $1 AND s.status_mode = 'auto'
+			GROUP BY s.id
+		) computed
+		WHERE services.id = computed.id AND services.status::text <> computed.status
+		RETURNING ` + serviceColumns
+
+	// Maintenance ranks below every impact.
+	levels := append([]string{string(Schemas.ServiceUnderMaintenance)}, Schemas.ImpactLevels...)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(query, orgId, pq.Array(levels), string(Schemas.ServiceUnderMaintenance))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changed []Schemas.Service
+	for rows.Next() {
+		var s Schemas.Service
+		if err := scanService(rows, &s); err != nil {
+			return nil, err
+		}
+		changed = append(changed, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, s := range changed {
+		if err := recordServiceStatus(tx, s.ID, s.Status); err != nil {
+			return nil, err
+		}
+	}
+	return changed, tx.Commit()
+}
+
+// OrgsWithStartedMaintenance lists the orgs with an unresolved maintenance
+// incident that started after since and by until, so their auto-mode services
+// can be recomputed when it begins.
+func OrgsWithStartedMaintenance(ctx context.Context, db *sql.DB, since, until time.Time) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT DISTINCT clerk_org_id FROM incidents
+		WHERE status = 'maintenance' AND merged_into IS NULL AND started_at > $1 AND started_at <= $2
+	`, since, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgIDs []string
+	for rows.Next() {
+		var orgID string
+		if err := rows.Scan(&orgID); err != nil {
+			return nil, err
+		}
+		orgIDs = append(orgIDs, orgID)
+	}
+	return orgIDs, rows.Err()
+}
+
+// serviceMissingOrStale tells apart a guarded UPDATE that matched nothing
+// because the service is gone from one that lost a version race.
+func serviceMissingOrStale(db Executor, serviceID int, orgId string) error {
+	var exists bool
+	err := db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM services WHERE id = $1 AND clerk_org_id = $2)`,
+		serviceID, orgId,
+	).Scan(&exists)
 	if err != nil {
 		return err
 	}
-	_, err = db.Exec("DELETE FROM services WHERE id = $1 AND clerk_org_id = $2", serviceID, orgId)
+	if exists {
+		return ErrVersionConflict
+	}
+	return sql.ErrNoRows
+}
+
+// ResolveServiceRef returns the ID of the org's service whose ID or slug is
+// ref, or sql.ErrNoRows when there is none.
+func ResolveServiceRef(db Executor, orgId, ref string) (int, error) {
+	var id int
+	err := db.QueryRow(
+		`SELECT id FROM services WHERE clerk_org_id = $1 AND (slug = $2 OR id::text = $2)`,
+		orgId, ref,
+	).Scan(&id)
+	return id, err
+}
+
+// AvailableServiceSlug returns slug if no service of the org uses it, else
+// the first free "slug-2", "slug-3", ...
+func AvailableServiceSlug(db Executor, orgId, slug string) (string, error) {
+	rows, err := db.Query(
+		`SELECT slug FROM services WHERE clerk_org_id = $1 AND (slug = $2 OR slug LIKE $2 || '-%')`,
+		orgId, slug,
+	)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := map[string]bool{}
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return "", err
+		}
+		taken[s] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	candidate := slug
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", slug, n)
+	}
+	return candidate, nil
+}
+
+// slugConflict turns a violation of the per-org slug index into ErrSlugTaken.
+func slugConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "services_org_slug_key" {
+		return ErrSlugTaken
+	}
 	return err
 }
+
+// DeleteService removes a service and its incident links. Unless force is
+// set it refuses with ErrServiceInUse while unresolved incidents reference the
+// service, returning those incidents. A forced delete notes the removal on the
+// timeline of each of them and returns the entries written.
+func DeleteService(db *sql.DB, serviceID int, orgId string, force bool, userId, fullName string) ([]Schemas.IncidentTitles, []Schemas.IncidentUpdateData, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var name string
+	err = tx.QueryRow(
+		`SELECT name FROM services WHERE id = $1 AND clerk_org_id = $2 FOR UPDATE`,
+		serviceID, orgId,
+	).Scan(&name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	open, err := openIncidentsForService(tx, serviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(open) > 0 && !force {
+		return open, nil, ErrServiceInUse
+	}
+
+	message, err := json.Marshal(Schemas.TimelineNote{
+		Note: fmt.Sprintf("Service %s was deleted and is no longer linked to this incident", name),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var entries []Schemas.IncidentUpdateData
+	for _, incident := range open {
+		entry, err := AddIncidentNote(tx, incident.ID, orgId, string(message), Schemas.VisibilityInternal, userId, fullName)
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if _, err := tx.Exec("DELETE FROM service_incidents WHERE service_id = $1", serviceID); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec("DELETE FROM services WHERE id = $1 AND clerk_org_id = $2", serviceID, orgId); err != nil {
+		return nil, nil, err
+	}
+
+	return open, entries, tx.Commit()
+}
+
+// openIncidentsForService lists the unresolved, unmerged incidents linked to
+// a service, oldest first.
+func openIncidentsForService(db Executor, serviceID int) ([]Schemas.IncidentTitles, error) {
+	rows, err := db.Query(`
+		SELECT i.id, i.title, i.status, i.created_at, i.labels, COALESCE(i.severity, '')
+		FROM service_incidents si
+		JOIN incidents i ON i.id = si.incident_id
+		WHERE si.service_id = $1 AND i.status <> 'resolved' AND i.merged_into IS NULL
+		ORDER BY i.created_at, i.id
+	`, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []Schemas.IncidentTitles
+	for rows.Next() {
+		var i Schemas.IncidentTitles
+		if err := rows.Scan(&i.ID, &i.Title, &i.Status, &i.CreatedAt, &i.Labels, &i.Severity); err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, i)
+	}
+
+	return incidents, rows.Err()
+}
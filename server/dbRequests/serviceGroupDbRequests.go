@@ -0,0 +1,79 @@
+package dbrequests
+
+import (
+	"database/sql"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+func CreateServiceGroup(db *sql.DB, orgID string, request Schemas.ServiceGroupRequest) (Schemas.ServiceGroup, error) {
+	group := Schemas.ServiceGroup{Name: request.Name, Position: request.Position}
+	err := db.QueryRow(
+		`INSERT INTO service_groups (clerk_org_id, name, position) VALUES ($1, $2, $3) RETURNING id`,
+		orgID, request.Name, request.Position,
+	).Scan(&group.ID)
+	return group, err
+}
+
+// GetServiceGroups lists the org's groups in display order, without their
+// services.
+func GetServiceGroups(db *sql.DB, orgID string) ([]Schemas.ServiceGroup, error) {
+	rows, err := db.Query(
+		`SELECT id, name, position FROM service_groups WHERE clerk_org_id = $1 ORDER BY position, name, id`,
+		orgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []Schemas.ServiceGroup
+	for rows.Next() {
+		var group Schemas.ServiceGroup
+		if err := rows.Scan(&group.ID, &group.Name, &group.Position); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+// UpdateServiceGroup renames or moves a group. It returns sql.ErrNoRows when
+// the org has no such group.
+func UpdateServiceGroup(db *sql.DB, groupID int, orgID string, request Schemas.ServiceGroupRequest) (Schemas.ServiceGroup, error) {
+	group := Schemas.ServiceGroup{ID: groupID, Name: request.Name, Position: request.Position}
+	result, err := db.Exec(
+		`UPDATE service_groups SET name = $1, position = $2, updated_at = NOW() WHERE id = $3 AND clerk_org_id = $4`,
+		request.Name, request.Position, groupID, orgID,
+	)
+	if err != nil {
+		return group, err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return group, sql.ErrNoRows
+	}
+	return group, nil
+}
+
+// DeleteServiceGroup removes a group; its services become ungrouped. It
+// returns sql.ErrNoRows when the org has no such group.
+func DeleteServiceGroup(db *sql.DB, groupID int, orgID string) error {
+	result, err := db.Exec(`DELETE FROM service_groups WHERE id = $1 AND clerk_org_id = $2`, groupID, orgID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func ServiceGroupExists(db *sql.DB, groupID int, orgID string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM service_groups WHERE id = $1 AND clerk_org_id = $2)`,
+		groupID, orgID,
+	).Scan(&exists)
+	return exists, err
+}
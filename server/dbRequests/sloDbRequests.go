@@ -0,0 +1,176 @@
+package dbrequests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/lib/pq"
+)
+
+// ErrSLOExists is returned when the service already has an SLO.
+var ErrSLOExists = errors.New("service already has an SLO")
+
+// sloColumns is the column list scanSLO expects; queries alias service_slos
+// as o and join services as s.
+const sloColumns = `o.id, o.service_id, o.target, o.window_days, o.alert_thresholds, o.alerted_threshold,
+	o.burn_rate_threshold_1h, o.burn_rate_threshold_24h, o.burn_alerted_1h, o.burn_alerted_24h,
+	o.created_at, s.clerk_org_id`
+
+func scanSLO(row rowScanner) (Schemas.SLO, error) {
+	var o Schemas.SLO
+	var thresholds pq.Float64Array
+	err := row.Scan(&o.ID, &o.ServiceID, &o.Target, &o.WindowDays, &thresholds, &o.AlertedThreshold,
+		&o.BurnRateThreshold1h, &o.BurnRateThreshold24h, &o.BurnAlerted1h, &o.BurnAlerted24h, &o.CreatedAt, &o.OrgID)
+	o.AlertThresholds = []float64(thresholds)
+	return o, err
+}
+
+func scanSLOs(rows *sql.Rows) ([]Schemas.SLO, error) {
+	defer rows.Close()
+
+	var slos []Schemas.SLO
+	for rows.Next() {
+		o, err := scanSLO(rows)
+		if err != nil {
+			return nil, err
+		}
+		slos = append(slos, o)
+	}
+	return slos, rows.Err()
+}
+
+// sloExists turns a violation of the one-SLO-per-service constraint into
+// ErrSLOExists.
+func sloExists(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "service_slos_service_id_key" {
+		return ErrSLOExists
+	}
+	return err
+}
+
+// CreateSLO stores a normalized request. The caller checks that the service
+// belongs to the org.
+func CreateSLO(db *sql.DB, request Schemas.SLORequest, orgID string) (Schemas.SLO, error) {
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO service_slos (service_id, target, window_days, alert_thresholds, burn_rate_threshold_1h, burn_rate_threshold_24h)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, request.ServiceID, request.Target, request.WindowDays, pq.Float64Array(request.AlertThresholds),
+		*request.BurnRateThreshold1h, *request.BurnRateThreshold24h).Scan(&id)
+	if err != nil {
+		return Schemas.SLO{}, sloExists(err)
+	}
+
+	return GetSLO(db, id, orgID)
+}
+
+func GetSLOsForOrg(db *sql.DB, orgID string) ([]Schemas.SLO, error) {
+	rows, err := db.Query(`
+		SELECT `+sloColumns+`
+		FROM service_slos o JOIN services s ON s.id = o.service_id
+		WHERE s.clerk_org_id = $1
+		ORDER BY s.display_order, s.id
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return scanSLOs(rows)
+}
+
+// GetAllSLOs lists the SLOs of every org, grouped by org.
+func GetAllSLOs(ctx context.Context, db *sql.DB) ([]Schemas.SLO, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+sloColumns+`
+		FROM service_slos o JOIN services s ON s.id = o.service_id
+		ORDER BY s.clerk_org_id, o.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return scanSLOs(rows)
+}
+
+func GetSLO(db *sql.DB, sloID int, orgID string) (Schemas.SLO, error) {
+	return scanSLO(db.QueryRow(`
+		SELECT `+sloColumns+`
+		FROM service_slos o JOIN services s ON s.id = o.service_id
+		WHERE o.id = $1 AND s.clerk_org_id = $2
+	`, sloID, orgID))
+}
+
+// UpdateSLO replaces an SLO's definition. Alerts start over, so thresholds
+// already crossed under the new definition are announced again. It returns
+// sql.ErrNoRows when the org has no such SLO.
+func UpdateSLO(db *sql.DB, sloID int, orgID string, request Schemas.SLORequest) (Schemas.SLO, error) {
+	result, err := db.Exec(`
+		UPDATE service_slos o
+		SET service_id = $1, target = $2, window_days = $3, alert_thresholds = $4,
+			burn_rate_threshold_1h = $5, burn_rate_threshold_24h = $6,
+			alerted_threshold = 0, burn_alerted_1h = false, burn_alerted_24h = false, updated_at = NOW()
+		FROM services s
+		WHERE s.id = o.service_id AND o.id = $7 AND s.clerk_org_id = $8
+	`, request.ServiceID, request.Target, request.WindowDays, pq.Float64Array(request.AlertThresholds),
+		*request.BurnRateThreshold1h, *request.BurnRateThreshold24h, sloID, orgID)
+	if err != nil {
+		return Schemas.SLO{}, sloExists(err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return Schemas.SLO{}, sql.ErrNoRows
+	}
+
+	return GetSLO(db, sloID, orgID)
+}
+
+// DeleteSLO removes an SLO. It returns sql.ErrNoRows when the org has no such
+// SLO.
+func DeleteSLO(db *sql.DB, sloID int, orgID string) error {
+	result, err := db.Exec(`
+		DELETE FROM service_slos o USING services s
+		WHERE s.id = o.service_id AND o.id = $1 AND s.clerk_org_id = $2
+	`, sloID, orgID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetSLOAlertedThreshold moves an SLO's alerted threshold from one value to
+// another. It reports false when the stored value was no longer from, i.e.
+// another evaluation got there first.
+func SetSLOAlertedThreshold(ctx context.Context, db *sql.DB, sloID int, from, to float64) (bool, error) {
+	result, err := db.ExecContext(ctx,
+		`UPDATE service_slos SET alerted_threshold = $3 WHERE id = $1 AND alerted_threshold = $2`,
+		sloID, from, to,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// SetSLOBurnAlerted sets whether an SLO's burn rate alert over window is
+// firing, moving it from one value to the other. It reports false when the
+// stored value was no longer from.
+func SetSLOBurnAlerted(ctx context.Context, db *sql.DB, sloID int, window string, from, to bool) (bool, error) {
+	column := "burn_alerted_1h"
+	if window == Schemas.SLOBurnWindow24h {
+		column = "burn_alerted_24h"
+	}
+	result, err := db.ExecContext(ctx,
+		`UPDATE service_slos SET `+column+` = $3 WHERE id = $1 AND `+column+` = $2`,
+		sloID, from, to,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,56 @@
+package dbrequests
+
+import (
+	"database/sql"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// recordServiceStatus appends status to the service's history unless it is
+// already the latest recorded status.
+func recordServiceStatus(db Executor, serviceID int, status Schemas.ServiceStatus) error {
+	_, err := db.Exec(`
+		INSERT INTO service_status_history (service_id, status)
+		SELECT $1, $2::service_status
+		WHERE $2::service_status IS DISTINCT FROM (
+			SELECT status FROM service_status_history
+			WHERE service_id = $1
+			ORDER BY changed_at DESC, id DESC
+			LIMIT 1
+		)
+	`, serviceID, status)
+	return err
+}
+
+// GetStatusHistoryForOrg returns the status changes of the org's services that
+// matter for [from, to): the change in effect at from and every later one
+// before to, grouped by service and ordered by time.
+func GetStatusHistoryForOrg(db *sql.DB, orgID string, from, to time.Time) (map[int][]Schemas.StatusChange, error) {
+	rows, err := db.Query(`
+		SELECT h.service_id, h.status, h.changed_at
+		FROM service_status_history h
+		JOIN services s ON s.id = h.service_id
+		WHERE s.clerk_org_id = $1 AND h.changed_at < $3
+		AND h.changed_at >= COALESCE((
+			SELECT MAX(p.changed_at) FROM service_status_history p
+			WHERE p.service_id = h.service_id AND p.changed_at <= $2
+		), '-infinity')
+		ORDER BY h.service_id, h.changed_at, h.id
+	`, orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := map[int][]Schemas.StatusChange{}
+	for rows.Next() {
+		var change Schemas.StatusChange
+		if err := rows.Scan(&change.ServiceID, &change.Status, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		history[change.ServiceID] = append(history[change.ServiceID], change)
+	}
+
+	return history, rows.Err()
+}
@@ -0,0 +1,107 @@
+package dbrequests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// systemUser is recorded as the author of incidents and timeline entries the
+// server writes on its own.
+const systemUser = "system"
+
+// OpenSystemIncident opens an investigating incident on behalf of the server,
+// e.g. for a failing monitor, links it to serviceID with the given impact and
+// writes note as its first public timeline entry. A non-empty detail, such as
+// the check's target and error, is only written as an internal entry. source
+// names the feature in the timeline.
+func OpenSystemIncident(ctx context.Context, db *sql.DB, orgID string, serviceID int, title, note, detail string, impact Schemas.Impact, source string) (Schemas.EditInstance, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Schemas.EditInstance{}, err
+	}
+	defer tx.Rollback()
+
+	incident := Schemas.EditInstance{Title: title, Description: note, Status: Schemas.IncidentInvestigating}
+	var version int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO incidents (title, description, status, started_at, clerk_org_id, created_by_clerk)
+		VALUES ($1, $2, $3, NOW(), $4, $5)
+		RETURNING id, started_at, version
+	`, title, note, incident.Status, orgID, systemUser).Scan(&incident.ID, &incident.StartedAt, &version)
+	if err != nil {
+		return Schemas.EditInstance{}, err
+	}
+	incident.Version = &version
+
+	id := int32(serviceID)
+	incident.LinkedServices = []Schemas.LinkedServiceIn{{ServiceID: &id, Impact: impact}}
+	if err := LinkIncidentServices(tx, incident.ID, incident.LinkedServices); err != nil {
+		return Schemas.EditInstance{}, err
+	}
+
+	if err := insertSystemNotes(tx, incident.ID, incident.Status, note, detail, source); err != nil {
+		return Schemas.EditInstance{}, err
+	}
+
+	return incident, tx.Commit()
+}
+
+// ResolveSystemIncident resolves an incident in the org with note as a
+// public timeline entry and detail, if any, as an internal one. It returns
+// false when the incident is gone or was already resolved or merged, e.g. by
+// a responder.
+func ResolveSystemIncident(ctx context.Context, db *sql.DB, orgID, incidentID, note, detail, source string) (Schemas.EditInstance, bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Schemas.EditInstance{}, false, err
+	}
+	defer tx.Rollback()
+
+	incident := Schemas.EditInstance{ID: incidentID}
+	var version int
+	var startedAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		UPDATE incidents
+		SET status = 'resolved', resolved_at = NOW(), updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND clerk_org_id = $2 AND status <> 'resolved' AND merged_into IS NULL
+		RETURNING title, COALESCE(description, ''), status, started_at, version
+	`, incidentID, orgID).Scan(&incident.Title, &incident.Description, &incident.Status, &startedAt, &version)
+	if err == sql.ErrNoRows {
+		return incident, false, nil
+	}
+	if err != nil {
+		return incident, false, err
+	}
+	incident.StartedAt = startedAt
+	incident.Version = &version
+
+	if err := insertSystemNotes(tx, incidentID, incident.Status, note, detail, source); err != nil {
+		return incident, false, err
+	}
+
+	return incident, true, tx.Commit()
+}
+
+// insertSystemNotes writes note as a public timeline entry and detail, unless
+// it is empty, as an internal one that stays off the status page and feeds.
+func insertSystemNotes(tx *sql.Tx, incidentID string, status Schemas.IncidentStatus, note, detail, source string) error {
+	if err := insertSystemNote(tx, incidentID, status, note, Schemas.VisibilityPublic, source); err != nil {
+		return err
+	}
+	if detail == "" {
+		return nil
+	}
+	return insertSystemNote(tx, incidentID, status, detail, Schemas.VisibilityInternal, source)
+}
+
+func insertSystemNote(tx *sql.Tx, incidentID string, status Schemas.IncidentStatus, note, visibility, source string) error {
+	message, err := json.Marshal(Schemas.TimelineNote{Note: note})
+	if err != nil {
+		return err
+	}
+	return InsertTimelineEntry(tx, string(message), incidentID, status, visibility, systemUser, source)
+}
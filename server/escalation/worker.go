@@ -0,0 +1,97 @@
+package escalation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/krnveersharma/Statuses/websocketsHandler"
+)
+
+// Worker periodically checks open, unacknowledged incidents against their
+// org's escalation policy and fires the steps that are due.
+type Worker struct {
+	DB       *sql.DB
+	Interval time.Duration
+}
+
+func NewWorker(db *sql.DB, interval time.Duration) *Worker {
+	return &Worker{DB: db, Interval: interval}
+}
+
+// Run evaluates policies every Interval until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.evaluate(ctx, time.Now()); err != nil {
+			log.Printf("[Escalation] %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) evaluate(ctx context.Context, now time.Time) error {
+	incidents, err := dbrequests.GetUnacknowledgedIncidents(ctx, w.DB)
+	if err != nil {
+		return fmt.Errorf("fetching unacknowledged incidents: %w", err)
+	}
+
+	for _, incident := range incidents {
+		for _, i := range dueSteps(incident, now) {
+			w.fire(ctx, incident, i, incident.Steps[i])
+		}
+	}
+	return nil
+}
+
+// dueSteps returns the indexes of steps whose delay has passed and whose ID
+// has not fired yet, in policy order.
+func dueSteps(incident dbrequests.UnacknowledgedIncident, now time.Time) []int {
+	var due []int
+	elapsed := now.Sub(incident.OpenedAt)
+	for i, step := range incident.Steps {
+		if elapsed < time.Duration(step.AfterMinutes)*time.Minute {
+			continue
+		}
+		if slices.Contains(incident.FiredSteps, step.ID) {
+			continue
+		}
+		due = append(due, i)
+	}
+	return due
+}
+
+func (w *Worker) fire(ctx context.Context, incident dbrequests.UnacknowledgedIncident, index int, step Schemas.EscalationStep) {
+	note := fmt.Sprintf("Escalated to %s: not acknowledged after %d minutes", step.Notify, step.AfterMinutes)
+	fired, err := dbrequests.RecordEscalation(ctx, w.DB, incident.IncidentID, incident.Status, step.ID, note)
+	if err != nil {
+		log.Printf("[Escalation] Failed to record step %s for incident %s: %v\n", step.ID, incident.IncidentID, err)
+		return
+	}
+	if !fired {
+		return
+	}
+
+	log.Printf("[Escalation] Incident %s escalated to %s\n", incident.IncidentID, step.Notify)
+	websocketsHandler.IncidentEscalated(incident.OrgID, Schemas.EscalationEvent{
+		IncidentID:   incident.IncidentID,
+		Title:        incident.Title,
+		Step:         index,
+		StepID:       step.ID,
+		AfterMinutes: step.AfterMinutes,
+		Notify:       step.Notify,
+		EscalatedAt:  time.Now(),
+	})
+}
@@ -0,0 +1,71 @@
+// Package maintenance puts services under maintenance when a scheduled
+// maintenance window begins.
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	"github.com/krnveersharma/Statuses/websocketsHandler"
+)
+
+// Watcher recomputes the auto-mode services of orgs whose maintenance
+// incidents have started since its last pass. Nothing else happens at a
+// maintenance's start time, so without it services would only change at the
+// next unrelated edit.
+type Watcher struct {
+	DB       *sql.DB
+	Interval time.Duration
+}
+
+func NewWatcher(db *sql.DB, interval time.Duration) *Watcher {
+	return &Watcher{DB: db, Interval: interval}
+}
+
+// Run checks for started maintenance every Interval until ctx is cancelled.
+// The first pass covers every maintenance already under way, in case it
+// started while the server was down.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	since := time.Time{}
+	for {
+		now := time.Now()
+		if err := w.check(ctx, since, now); err != nil {
+			log.Printf("[Maintenance] %v\n", err)
+		} else {
+			since = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) check(ctx context.Context, since, now time.Time) error {
+	orgIDs, err := dbrequests.OrgsWithStartedMaintenance(ctx, w.DB, since, now)
+	if err != nil {
+		return fmt.Errorf("finding started maintenance: %w", err)
+	}
+
+	for _, orgID := range orgIDs {
+		changed, err := dbrequests.RecomputeAutoStatuses(w.DB, orgID)
+		if err != nil {
+			log.Printf("[Maintenance] Failed to recompute service statuses of org %s: %v\n", orgID, err)
+			continue
+		}
+		for _, service := range changed {
+			websocketsHandler.UpdateService(strconv.Itoa(service.ID), orgID, service)
+		}
+	}
+	return nil
+}
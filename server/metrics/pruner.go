@@ -0,0 +1,39 @@
+// Package metrics keeps service metric rollups within their retention.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+)
+
+// Pruner deletes rollups that have outlived their resolution's retention.
+type Pruner struct {
+	DB       *sql.DB
+	Interval time.Duration
+}
+
+func NewPruner(db *sql.DB, interval time.Duration) *Pruner {
+	return &Pruner{DB: db, Interval: interval}
+}
+
+// Run prunes every Interval until ctx is cancelled.
+func (p *Pruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := dbrequests.PruneMetricRollups(ctx, p.DB, time.Now()); err != nil {
+			log.Printf("[Metrics] Failed to prune rollups: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
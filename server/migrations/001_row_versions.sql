@@ -0,0 +1,3 @@
+-- Row versions for optimistic concurrency control on incident and service edits.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
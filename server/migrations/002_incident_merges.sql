@@ -0,0 +1,3 @@
+-- Incidents merged into another incident keep their row as a redirect.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS merged_into integer REFERENCES incidents(id);
+CREATE INDEX IF NOT EXISTS incidents_merged_into_idx ON incidents (merged_into);
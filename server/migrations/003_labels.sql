@@ -0,0 +1,5 @@
+-- Free-form key/value labels on incidents and services.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS labels jsonb NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS labels jsonb NOT NULL DEFAULT '{}'::jsonb;
+CREATE INDEX IF NOT EXISTS incidents_labels_idx ON incidents USING gin (labels jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS services_labels_idx ON services USING gin (labels jsonb_path_ops);
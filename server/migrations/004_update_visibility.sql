@@ -0,0 +1,3 @@
+-- Timeline entries can be internal notes hidden from public read paths.
+ALTER TABLE incident_updates ADD COLUMN IF NOT EXISTS visibility text NOT NULL DEFAULT 'public'
+	CHECK (visibility IN ('public', 'internal'));
@@ -0,0 +1,10 @@
+-- Severity for MTTA/MTTR reporting, and resolved_at for incidents resolved
+-- before the server started maintaining it.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS severity text
+	CHECK (severity IN ('critical', 'major', 'minor'));
+
+UPDATE incidents i SET resolved_at = (
+	SELECT MIN(u.created_at) FROM incident_updates u
+	WHERE u.incident_id = i.id AND u.status = 'resolved'
+)
+WHERE i.status = 'resolved' AND i.resolved_at IS NULL;
@@ -0,0 +1,15 @@
+-- Per-org escalation policy and the steps fired for each incident. Fired
+-- steps are recorded by the step's stable ID, so editing the policy neither
+-- skips new steps nor re-fires shifted ones.
+CREATE TABLE IF NOT EXISTS escalation_policies (
+	clerk_org_id text PRIMARY KEY,
+	steps jsonb NOT NULL,
+	updated_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS incident_escalations (
+	incident_id integer NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+	step_id text NOT NULL,
+	escalated_at timestamptz NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (incident_id, step_id)
+);
@@ -0,0 +1,13 @@
+-- Metadata of files attached to incidents; the bytes live in the blob store.
+CREATE TABLE IF NOT EXISTS incident_attachments (
+	id serial PRIMARY KEY,
+	incident_id integer NOT NULL REFERENCES incidents(id),
+	update_id integer REFERENCES incident_updates(id) ON DELETE SET NULL,
+	file_name text NOT NULL,
+	content_type text NOT NULL,
+	size_bytes bigint NOT NULL,
+	storage_key text NOT NULL UNIQUE,
+	created_at timestamptz NOT NULL DEFAULT NOW(),
+	created_by_clerk text NOT NULL
+);
+CREATE INDEX IF NOT EXISTS incident_attachments_incident_idx ON incident_attachments (incident_id);
@@ -0,0 +1,3 @@
+-- Per-service impact of an incident, using the service status values.
+ALTER TABLE service_incidents ADD COLUMN IF NOT EXISTS impact text NOT NULL DEFAULT 'partial outage'
+	CHECK (impact IN ('degraded performance', 'partial outage', 'major outage'));
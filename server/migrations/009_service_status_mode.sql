@@ -0,0 +1,3 @@
+-- Services in auto mode take their status from linked unresolved incidents.
+ALTER TABLE services ADD COLUMN IF NOT EXISTS status_mode text NOT NULL DEFAULT 'manual'
+	CHECK (status_mode IN ('manual', 'auto'));
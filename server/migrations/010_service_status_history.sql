@@ -0,0 +1,14 @@
+-- Every status a service has had, with the time it started. Existing services
+-- start their history at migration time.
+CREATE TABLE IF NOT EXISTS service_status_history (
+	id serial PRIMARY KEY,
+	service_id int NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+	status service_status NOT NULL,
+	changed_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS service_status_history_service_idx ON service_status_history (service_id, changed_at);
+
+INSERT INTO service_status_history (service_id, status)
+SELECT id, status FROM services s
+WHERE NOT EXISTS (SELECT 1 FROM service_status_history h WHERE h.service_id = s.id);
@@ -0,0 +1,13 @@
+-- Named, ordered groups of services for the status page.
+CREATE TABLE IF NOT EXISTS service_groups (
+	id serial PRIMARY KEY,
+	clerk_org_id text NOT NULL,
+	name varchar NOT NULL,
+	position int NOT NULL DEFAULT 0,
+	created_at timestamptz NOT NULL DEFAULT NOW(),
+	updated_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS service_groups_org_idx ON service_groups (clerk_org_id, position);
+
+ALTER TABLE services ADD COLUMN IF NOT EXISTS group_id int REFERENCES service_groups(id) ON DELETE SET NULL;
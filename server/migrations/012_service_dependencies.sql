@@ -0,0 +1,10 @@
+-- service_id depends on depends_on_id: an incident on the latter may impact
+-- the former. Cycles are rejected by the API.
+CREATE TABLE IF NOT EXISTS service_dependencies (
+	service_id int NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+	depends_on_id int NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+	PRIMARY KEY (service_id, depends_on_id),
+	CHECK (service_id <> depends_on_id)
+);
+
+CREATE INDEX IF NOT EXISTS service_dependencies_depends_on_idx ON service_dependencies (depends_on_id);
@@ -0,0 +1,35 @@
+-- Synthetic checks run by the server's monitor scheduler.
+CREATE TABLE IF NOT EXISTS monitors (
+	id serial PRIMARY KEY,
+	service_id int NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+	kind text NOT NULL CHECK (kind IN ('http', 'tcp', 'dns')),
+	target text NOT NULL,
+	expected_status int NOT NULL DEFAULT 0,
+	body_contains text NOT NULL DEFAULT '',
+	interval_seconds int NOT NULL DEFAULT 60,
+	timeout_seconds int NOT NULL DEFAULT 10,
+	failure_threshold int NOT NULL DEFAULT 3,
+	failure_status text NOT NULL DEFAULT 'major outage'
+		CHECK (failure_status IN ('degraded performance', 'partial outage', 'major outage')),
+	open_incident boolean NOT NULL DEFAULT false,
+	enabled boolean NOT NULL DEFAULT true,
+	consecutive_failures int NOT NULL DEFAULT 0,
+	tripped boolean NOT NULL DEFAULT false,
+	incident_id int REFERENCES incidents(id) ON DELETE SET NULL,
+	last_checked_at timestamptz,
+	created_at timestamptz NOT NULL DEFAULT NOW(),
+	updated_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS monitors_service_idx ON monitors (service_id);
+
+CREATE TABLE IF NOT EXISTS monitor_results (
+	id bigserial PRIMARY KEY,
+	monitor_id int NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+	checked_at timestamptz NOT NULL DEFAULT NOW(),
+	success boolean NOT NULL,
+	latency_ms double precision NOT NULL,
+	error text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS monitor_results_monitor_idx ON monitor_results (monitor_id, checked_at);
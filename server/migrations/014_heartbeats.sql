@@ -0,0 +1,19 @@
+-- Dead man's switches: jobs ping with the token, and a heartbeat that stays
+-- silent longer than grace_seconds marks its service down. Only a SHA-256
+-- hash of the token is stored.
+CREATE TABLE IF NOT EXISTS heartbeats (
+	id serial PRIMARY KEY,
+	service_id int NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+	name text NOT NULL DEFAULT '',
+	token_hash text NOT NULL UNIQUE,
+	grace_seconds int NOT NULL DEFAULT 300,
+	failure_status text NOT NULL DEFAULT 'major outage'
+		CHECK (failure_status IN ('degraded performance', 'partial outage', 'major outage')),
+	open_incident boolean NOT NULL DEFAULT true,
+	last_ping_at timestamptz,
+	tripped boolean NOT NULL DEFAULT false,
+	incident_id int REFERENCES incidents(id) ON DELETE SET NULL,
+	created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS heartbeats_service_idx ON heartbeats (service_id);
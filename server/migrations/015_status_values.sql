@@ -0,0 +1,4 @@
+-- Make sure both enums carry every value the API accepts. Run outside a
+-- transaction on PostgreSQL versions before 12.
+ALTER TYPE service_status ADD VALUE IF NOT EXISTS 'under maintenance';
+ALTER TYPE incident_status ADD VALUE IF NOT EXISTS 'maintenance';
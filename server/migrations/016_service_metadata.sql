@@ -0,0 +1,10 @@
+-- Descriptive metadata, display order and public visibility for services.
+ALTER TABLE services ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS public_url text NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS runbook_url text NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS owner_team text NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS contact text NOT NULL DEFAULT '';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS display_order int NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS hidden boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS services_org_display_order_idx ON services (clerk_org_id, display_order, id);
@@ -0,0 +1,22 @@
+-- Per-org unique slugs for services, derived from the name for existing rows.
+ALTER TABLE services ADD COLUMN IF NOT EXISTS slug text;
+
+WITH derived AS (
+	SELECT id, clerk_org_id,
+		left(trim(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), 50) AS base
+	FROM services
+	WHERE slug IS NULL
+), named AS (
+	SELECT id,
+		CASE WHEN base ~ '[a-z]' THEN base ELSE trim(BOTH '-' FROM 'service-' || base) END AS base,
+		row_number() OVER (PARTITION BY clerk_org_id, base ORDER BY id) AS n
+	FROM derived
+)
+UPDATE services s
+SET slug = CASE WHEN named.n = 1 THEN named.base ELSE named.base || '-' || s.id END
+FROM named
+WHERE s.id = named.id;
+
+ALTER TABLE services ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS services_org_slug_key ON services (clerk_org_id, slug);
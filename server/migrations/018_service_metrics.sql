@@ -0,0 +1,28 @@
+-- Numeric data points pushed for services (latency, error rate, ...), kept
+-- only as per-minute, per-hour and per-day rollups. Pushes authenticate with
+-- an ingest key; only a SHA-256 hash of each key is stored.
+CREATE TABLE IF NOT EXISTS ingest_keys (
+	id serial PRIMARY KEY,
+	clerk_org_id text NOT NULL,
+	name text NOT NULL DEFAULT '',
+	token_hash text NOT NULL UNIQUE,
+	created_by_clerk text NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT NOW(),
+	last_used_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS ingest_keys_org_idx ON ingest_keys (clerk_org_id);
+
+CREATE TABLE IF NOT EXISTS service_metric_rollups (
+	service_id int NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+	metric text NOT NULL,
+	resolution text NOT NULL CHECK (resolution IN ('minute', 'hour', 'day')),
+	bucket timestamptz NOT NULL,
+	count bigint NOT NULL,
+	sum double precision NOT NULL,
+	min double precision NOT NULL,
+	max double precision NOT NULL,
+	PRIMARY KEY (service_id, metric, resolution, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS service_metric_rollups_prune_idx ON service_metric_rollups (resolution, bucket);
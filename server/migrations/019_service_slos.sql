@@ -0,0 +1,15 @@
+-- Availability objectives per service, evaluated against status history.
+CREATE TABLE IF NOT EXISTS service_slos (
+	id serial PRIMARY KEY,
+	service_id int NOT NULL UNIQUE REFERENCES services(id) ON DELETE CASCADE,
+	target double precision NOT NULL CHECK (target > 0 AND target < 100),
+	window_days int NOT NULL DEFAULT 30,
+	alert_thresholds double precision[] NOT NULL DEFAULT '{50,75,90,100}',
+	alerted_threshold double precision NOT NULL DEFAULT 0,
+	burn_rate_threshold_1h double precision NOT NULL DEFAULT 14.4,
+	burn_rate_threshold_24h double precision NOT NULL DEFAULT 6,
+	burn_alerted_1h boolean NOT NULL DEFAULT false,
+	burn_alerted_24h boolean NOT NULL DEFAULT false,
+	created_at timestamptz NOT NULL DEFAULT NOW(),
+	updated_at timestamptz NOT NULL DEFAULT NOW()
+);
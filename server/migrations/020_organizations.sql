@@ -0,0 +1,9 @@
+-- Clerk organizations as seen on authenticated requests, so public pages can
+-- be looked up by org slug without Clerk. The public page is opt-in.
+CREATE TABLE IF NOT EXISTS organizations (
+	clerk_org_id text PRIMARY KEY,
+	slug text NOT NULL UNIQUE,
+	public_page boolean NOT NULL DEFAULT false,
+	created_at timestamptz NOT NULL DEFAULT NOW(),
+	updated_at timestamptz NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,152 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// maxBodyBytes bounds how much of an HTTP response is searched for
+// BodyContains.
+const maxBodyBytes = 1 << 20
+
+// errForbiddenAddress is reported instead of the underlying dial error, so
+// results do not reveal what internal names resolve to.
+var errForbiddenAddress = errors.New("target resolves to an address monitors may not reach")
+
+// Prober runs checks. Every connection it makes, including those made while
+// following redirects, goes through Dialer, whose Control hook refuses
+// addresses Allowed rejects after DNS resolution. A name that resolves to an
+// internal address, now or later, is therefore never reached.
+type Prober struct {
+	Dialer  *net.Dialer
+	Client  *http.Client
+	Allowed func(netip.Addr) bool
+}
+
+// NewProber returns a prober that only reaches public addresses.
+func NewProber() *Prober {
+	return newProber(Schemas.PublicAddress)
+}
+
+func newProber(allowed func(netip.Addr) bool) *Prober {
+	p := &Prober{Allowed: allowed}
+	p.Dialer = &net.Dialer{Control: p.control}
+	p.Client = &http.Client{
+		// Each check carries its own timeout through its context. Proxies
+		// from the environment are not used, as they would dial for us.
+		Transport: &http.Transport{
+			DialContext:       p.Dialer.DialContext,
+			ForceAttemptHTTP2: true,
+		},
+	}
+	return p
+}
+
+func (p *Prober) control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !p.Allowed(addrPort.Addr()) {
+		return errForbiddenAddress
+	}
+	return nil
+}
+
+// Check runs one probe for the monitor and reports how it went.
+func (p *Prober) Check(ctx context.Context, monitor Schemas.Monitor) Schemas.MonitorResult {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(monitor.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	switch monitor.Kind {
+	case "http":
+		err = p.checkHTTP(ctx, monitor)
+	case "tcp":
+		err = p.checkTCP(ctx, monitor.Target)
+	case "dns":
+		err = p.checkDNS(ctx, monitor.Target)
+	default:
+		err = fmt.Errorf("unknown monitor kind %q", monitor.Kind)
+	}
+	if errors.Is(err, errForbiddenAddress) {
+		err = errForbiddenAddress
+	}
+
+	result := Schemas.MonitorResult{
+		MonitorID: monitor.ID,
+		CheckedAt: start,
+		Success:   err == nil,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (p *Prober) checkHTTP(ctx context.Context, monitor Schemas.Monitor) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, monitor.Target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "Statuses-Monitor/1.0")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if monitor.ExpectedStatus != 0 && resp.StatusCode != monitor.ExpectedStatus {
+		return fmt.Errorf("got status %d, want %d", resp.StatusCode, monitor.ExpectedStatus)
+	}
+	if monitor.ExpectedStatus == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return fmt.Errorf("got status %d, want 2xx", resp.StatusCode)
+	}
+
+	if monitor.BodyContains != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		if err != nil {
+			return fmt.Errorf("reading body: %w", err)
+		}
+		if !strings.Contains(string(body), monitor.BodyContains) {
+			return errors.New("response body does not contain the expected text")
+		}
+	}
+	return nil
+}
+
+func (p *Prober) checkTCP(ctx context.Context, address string) error {
+	conn, err := p.Dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkDNS passes when the name resolves, and only to addresses the prober
+// may reach, so it cannot be used to map internal names.
+func (p *Prober) checkDNS(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return errors.New("no addresses found")
+	}
+	for _, addr := range addrs {
+		if !p.Allowed(addr) {
+			return errForbiddenAddress
+		}
+	}
+	return nil
+}
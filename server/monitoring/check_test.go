@@ -0,0 +1,150 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// allowAll lets tests reach their listeners on loopback.
+func allowAll(netip.Addr) bool { return true }
+
+func TestCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "all systems go")
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		monitor Schemas.Monitor
+		success bool
+	}{
+		{"http 2xx", Schemas.Monitor{Kind: "http", Target: server.URL + "/ok"}, true},
+		{"http 5xx", Schemas.Monitor{Kind: "http", Target: server.URL + "/fail"}, false},
+		{"http expected status", Schemas.Monitor{Kind: "http", Target: server.URL + "/fail", ExpectedStatus: 500}, true},
+		{"http unexpected status", Schemas.Monitor{Kind: "http", Target: server.URL + "/ok", ExpectedStatus: 204}, false},
+		{"http body contains", Schemas.Monitor{Kind: "http", Target: server.URL + "/ok", BodyContains: "systems go"}, true},
+		{"http body missing", Schemas.Monitor{Kind: "http", Target: server.URL + "/ok", BodyContains: "outage"}, false},
+		{"http redirect", Schemas.Monitor{Kind: "http", Target: server.URL + "/moved", BodyContains: "systems go"}, true},
+		{"tcp open", Schemas.Monitor{Kind: "tcp", Target: listener.Addr().String()}, true},
+		{"tcp closed", Schemas.Monitor{Kind: "tcp", Target: closedAddr}, false},
+		{"dns resolves", Schemas.Monitor{Kind: "dns", Target: "localhost"}, true},
+		{"dns unknown", Schemas.Monitor{Kind: "dns", Target: "no-such-host.invalid"}, false},
+		{"unknown kind", Schemas.Monitor{Kind: "icmp", Target: "localhost"}, false},
+	}
+
+	prober := newProber(allowAll)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.monitor.ID = 7
+			tt.monitor.TimeoutSeconds = 5
+			result := prober.Check(context.Background(), tt.monitor)
+			if result.Success != tt.success {
+				t.Fatalf("Success = %v, want %v (error %q)", result.Success, tt.success, result.Error)
+			}
+			if result.MonitorID != 7 {
+				t.Errorf("MonitorID = %d, want 7", result.MonitorID)
+			}
+			if tt.success != (result.Error == "") {
+				t.Errorf("Error = %q with Success %v", result.Error, result.Success)
+			}
+		})
+	}
+}
+
+func TestCheckRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a monitor reached a loopback server")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		monitor Schemas.Monitor
+	}{
+		{"http", Schemas.Monitor{Kind: "http", Target: server.URL}},
+		{"tcp", Schemas.Monitor{Kind: "tcp", Target: server.Listener.Addr().String()}},
+		{"dns", Schemas.Monitor{Kind: "dns", Target: "localhost"}},
+	}
+
+	prober := NewProber()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.monitor.TimeoutSeconds = 5
+			result := prober.Check(context.Background(), tt.monitor)
+			if result.Success {
+				t.Fatal("check of a loopback target succeeded")
+			}
+			if result.Error != errForbiddenAddress.Error() {
+				t.Errorf("Error = %q, want %q", result.Error, errForbiddenAddress)
+			}
+		})
+	}
+}
+
+func TestNextTransition(t *testing.T) {
+	failed := Schemas.MonitorResult{Success: false}
+	passed := Schemas.MonitorResult{Success: true}
+
+	tests := []struct {
+		name     string
+		monitor  Schemas.Monitor
+		result   Schemas.MonitorResult
+		expected transition
+	}{
+		{"failure below threshold", Schemas.Monitor{Enabled: true, FailureThreshold: 3, ConsecutiveFailures: 2}, failed, transitionNone},
+		{"failure at threshold", Schemas.Monitor{Enabled: true, FailureThreshold: 3, ConsecutiveFailures: 3}, failed, transitionTrip},
+		{"failure past threshold", Schemas.Monitor{Enabled: true, FailureThreshold: 3, ConsecutiveFailures: 5}, failed, transitionTrip},
+		{"threshold of one", Schemas.Monitor{Enabled: true, FailureThreshold: 1, ConsecutiveFailures: 1}, failed, transitionTrip},
+		{"already tripped", Schemas.Monitor{Enabled: true, FailureThreshold: 3, ConsecutiveFailures: 4, Tripped: true}, failed, transitionNone},
+		{"disabled", Schemas.Monitor{Enabled: false, FailureThreshold: 3, ConsecutiveFailures: 3}, failed, transitionNone},
+		{"success while tripped", Schemas.Monitor{Enabled: true, FailureThreshold: 3, Tripped: true}, passed, transitionRecover},
+		{"success while disabled and tripped", Schemas.Monitor{Enabled: false, FailureThreshold: 3, Tripped: true}, passed, transitionRecover},
+		{"success while healthy", Schemas.Monitor{Enabled: true, FailureThreshold: 3}, passed, transitionNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextTransition(tt.monitor, tt.result); got != tt.expected {
+				t.Errorf("nextTransition() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package monitoring
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/krnveersharma/Statuses/websocketsHandler"
+)
+
+// outage describes what a failing check does to its service: Status is the
+// service status it sets, and with OpenIncident an incident titled Title is
+// opened with Note as its first entry. Title and Note are public; Detail, if
+// set, goes on the timeline as an internal entry. Source names the check in
+// timelines.
+type outage struct {
+	OrgID        string
+	ServiceID    int
+	Status       Schemas.ServiceStatus
+	OpenIncident bool
+	Title        string
+	Note         string
+	Detail       string
+	Source       string
+}
+
+// markDown applies the outage and returns the ID of the incident it opened,
+// if any. Services in auto mode keep their computed status and only get the
+// incident.
+func markDown(ctx context.Context, db *sql.DB, o outage) (*string, error) {
+	var incidentID *string
+	if o.OpenIncident {
+		incident, err := dbrequests.OpenSystemIncident(ctx, db, o.OrgID, o.ServiceID, o.Title, o.Note, o.Detail, Schemas.Impact(o.Status), o.Source)
+		if err != nil {
+			return nil, fmt.Errorf("opening incident: %w", err)
+		}
+		incidentID = &incident.ID
+		websocketsHandler.CreateIncident(o.OrgID, incident)
+	}
+
+	service, err := dbrequests.GetServiceByID(db, strconv.Itoa(o.ServiceID), o.OrgID)
+	if err != nil {
+		return incidentID, fmt.Errorf("loading service: %w", err)
+	}
+	if service.StatusMode != Schemas.StatusModeAuto && o.Status.Worse(service.Status) {
+		if err := setServiceStatus(db, o.OrgID, o.ServiceID, o.Status); err != nil {
+			return incidentID, err
+		}
+	}
+
+	refreshAutoStatuses(db, o.OrgID)
+	return incidentID, nil
+}
+
+// markUp undoes markDown once the check passes again: it resolves the
+// incident it opened and, unless stillDown says another check keeps the
+// service down or someone changed the status meanwhile, sets the service
+// back to operational.
+func markUp(ctx context.Context, db *sql.DB, o outage, incidentID *string, stillDown bool) error {
+	if incidentID != nil {
+		incident, resolved, err := dbrequests.ResolveSystemIncident(ctx, db, o.OrgID, *incidentID, o.Note, o.Detail, o.Source)
+		if err != nil {
+			return fmt.Errorf("resolving incident %s: %w", *incidentID, err)
+		}
+		if resolved {
+			websocketsHandler.UpdateIncident(incident.ID, o.OrgID, incident)
+		}
+	}
+
+	if !stillDown {
+		service, err := dbrequests.GetServiceByID(db, strconv.Itoa(o.ServiceID), o.OrgID)
+		if err != nil {
+			return fmt.Errorf("loading service: %w", err)
+		}
+		if service.StatusMode != Schemas.StatusModeAuto && service.Status == o.Status {
+			if err := setServiceStatus(db, o.OrgID, o.ServiceID, Schemas.ServiceOperational); err != nil {
+				return err
+			}
+		}
+	}
+
+	refreshAutoStatuses(db, o.OrgID)
+	return nil
+}
+
+func setServiceStatus(db *sql.DB, orgID string, serviceID int, status Schemas.ServiceStatus) error {
+	service, err := dbrequests.PatchService(db, serviceID, orgID, Schemas.ServicePatch{Status: &status}, nil)
+	if err != nil {
+		return fmt.Errorf("setting service %d to %s: %w", serviceID, status, err)
+	}
+	websocketsHandler.UpdateService(strconv.Itoa(serviceID), orgID, service)
+	return nil
+}
+
+// refreshAutoStatuses recomputes the org's auto-mode services after a check
+// opened or resolved an incident and broadcasts those whose status moved.
+func refreshAutoStatuses(db *sql.DB, orgID string) {
+	changed, err := dbrequests.RecomputeAutoStatuses(db, orgID)
+	if err != nil {
+		log.Printf("[Monitoring] Failed to recompute service statuses: %v\n", err)
+		return
+	}
+	for _, service := range changed {
+		websocketsHandler.UpdateService(strconv.Itoa(service.ID), orgID, service)
+	}
+}
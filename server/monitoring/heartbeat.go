@@ -0,0 +1,134 @@
+package monitoring
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// HeartbeatChecker marks services down when their heartbeats go silent for
+// longer than the grace period.
+type HeartbeatChecker struct {
+	DB       *sql.DB
+	Interval time.Duration
+}
+
+func NewHeartbeatChecker(db *sql.DB, interval time.Duration) *HeartbeatChecker {
+	return &HeartbeatChecker{DB: db, Interval: interval}
+}
+
+// Run looks for overdue heartbeats every Interval until ctx is cancelled.
+func (c *HeartbeatChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.check(ctx); err != nil {
+			log.Printf("[Heartbeat] %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *HeartbeatChecker) check(ctx context.Context) error {
+	overdue, err := dbrequests.ClaimOverdueHeartbeats(ctx, c.DB)
+	if err != nil {
+		return fmt.Errorf("claiming overdue heartbeats: %w", err)
+	}
+
+	for _, heartbeat := range overdue {
+		c.trip(ctx, heartbeat)
+	}
+	return nil
+}
+
+func (c *HeartbeatChecker) trip(ctx context.Context, heartbeat Schemas.Heartbeat) {
+	log.Printf("[Heartbeat] Heartbeat %d of service %d is overdue\n", heartbeat.ID, heartbeat.ServiceID)
+
+	o := heartbeatOutage(heartbeat)
+	o.Note = fmt.Sprintf("No heartbeat received from %s for %d seconds", heartbeatName(heartbeat), heartbeat.GraceSeconds)
+	incidentID, err := markDown(ctx, c.DB, o)
+	if err != nil {
+		log.Printf("[Heartbeat] Failed to mark service %d down: %v\n", heartbeat.ServiceID, err)
+	}
+
+	stillTripped, err := dbrequests.SetHeartbeatIncident(ctx, c.DB, heartbeat.ID, incidentID)
+	if err != nil {
+		log.Printf("[Heartbeat] Failed to save state of heartbeat %d: %v\n", heartbeat.ID, err)
+		return
+	}
+	if !stillTripped {
+		// A ping arrived, or the heartbeat was deleted, while the service was
+		// being marked down. Its own resume may have run before markDown, so
+		// undo the outage here.
+		heartbeat.IncidentID = incidentID
+		HeartbeatResumed(ctx, c.DB, heartbeat)
+	}
+}
+
+// HeartbeatResumed undoes a tripped heartbeat after a ping: it resolves the
+// incident opened for it and, unless other checks still fail, sets the
+// service back to operational.
+func HeartbeatResumed(ctx context.Context, db *sql.DB, heartbeat Schemas.Heartbeat) {
+	log.Printf("[Heartbeat] Heartbeat %d of service %d resumed\n", heartbeat.ID, heartbeat.ServiceID)
+	releaseHeartbeat(ctx, db, heartbeat, fmt.Sprintf("Heartbeats from %s resumed", heartbeatName(heartbeat)))
+}
+
+// HeartbeatDeleted undoes a tripped heartbeat that was deleted, the same way
+// HeartbeatResumed does.
+func HeartbeatDeleted(ctx context.Context, db *sql.DB, heartbeat Schemas.Heartbeat) {
+	log.Printf("[Heartbeat] Tripped heartbeat %d of service %d was deleted\n", heartbeat.ID, heartbeat.ServiceID)
+	releaseHeartbeat(ctx, db, heartbeat, fmt.Sprintf("Heartbeat %s was deleted", heartbeatName(heartbeat)))
+}
+
+// HeartbeatReleased undoes a tripped heartbeat that was moved to another
+// service or given another failure status, the same way HeartbeatResumed
+// does. The heartbeat's own state must already be cleared.
+func HeartbeatReleased(ctx context.Context, db *sql.DB, heartbeat Schemas.Heartbeat, reason string) {
+	log.Printf("[Heartbeat] Tripped heartbeat %d %s, releasing service %d\n", heartbeat.ID, reason, heartbeat.ServiceID)
+	releaseHeartbeat(ctx, db, heartbeat, fmt.Sprintf("Heartbeat %s %s", heartbeatName(heartbeat), reason))
+}
+
+// releaseHeartbeat resolves the heartbeat's incident and, unless other checks
+// still fail, sets its service back to operational, with note on the timeline.
+func releaseHeartbeat(ctx context.Context, db *sql.DB, heartbeat Schemas.Heartbeat, note string) {
+	stillDown, err := dbrequests.OtherTrippedChecks(ctx, db, heartbeat.ServiceID, 0, heartbeat.ID)
+	if err != nil {
+		log.Printf("[Heartbeat] Failed to check other checks of service %d: %v\n", heartbeat.ServiceID, err)
+		stillDown = true
+	}
+
+	o := heartbeatOutage(heartbeat)
+	o.Note = note
+	if err := markUp(ctx, db, o, heartbeat.IncidentID, stillDown); err != nil {
+		log.Printf("[Heartbeat] Failed to mark service %d up: %v\n", heartbeat.ServiceID, err)
+	}
+}
+
+func heartbeatOutage(heartbeat Schemas.Heartbeat) outage {
+	return outage{
+		OrgID:        heartbeat.OrgID,
+		ServiceID:    heartbeat.ServiceID,
+		Status:       Schemas.ServiceStatus(heartbeat.FailureStatus),
+		OpenIncident: heartbeat.OpenIncident,
+		Title:        fmt.Sprintf("Missed heartbeat: %s", heartbeatName(heartbeat)),
+		Source:       "Heartbeat",
+	}
+}
+
+func heartbeatName(heartbeat Schemas.Heartbeat) string {
+	if heartbeat.Name != "" {
+		return heartbeat.Name
+	}
+	return fmt.Sprintf("heartbeat %d", heartbeat.ID)
+}
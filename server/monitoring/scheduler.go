@@ -0,0 +1,182 @@
+// Package monitoring runs synthetic checks against services, watches
+// heartbeats, and turns failures into service status changes and incidents.
+package monitoring
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// resultRetention is how long check results are kept.
+const resultRetention = 30 * 24 * time.Hour
+
+// Scheduler wakes up every Tick, claims the monitors that are due and runs
+// their checks concurrently.
+type Scheduler struct {
+	DB     *sql.DB
+	Tick   time.Duration
+	Prober *Prober
+}
+
+func NewScheduler(db *sql.DB, tick time.Duration) *Scheduler {
+	return &Scheduler{
+		DB:     db,
+		Tick:   tick,
+		Prober: NewProber(),
+	}
+}
+
+// Run schedules checks until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Tick)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+	for {
+		if err := s.runDue(ctx); err != nil {
+			log.Printf("[Monitoring] %v\n", err)
+		}
+		if time.Since(lastPrune) > time.Hour {
+			if err := dbrequests.PruneMonitorResults(ctx, s.DB, time.Now().Add(-resultRetention)); err != nil {
+				log.Printf("[Monitoring] Failed to prune results: %v\n", err)
+			}
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runDue(ctx context.Context) error {
+	monitors, err := dbrequests.ClaimDueMonitors(ctx, s.DB)
+	if err != nil {
+		return fmt.Errorf("claiming due monitors: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, monitor := range monitors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.run(ctx, monitor)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (s *Scheduler) run(ctx context.Context, monitor Schemas.Monitor) {
+	result := s.Prober.Check(ctx, monitor)
+	monitor, err := dbrequests.RecordMonitorResult(ctx, s.DB, result)
+	if err != nil {
+		log.Printf("[Monitoring] Failed to record result of monitor %d: %v\n", result.MonitorID, err)
+		return
+	}
+
+	switch nextTransition(monitor, result) {
+	case transitionTrip:
+		s.trip(ctx, monitor, result)
+	case transitionRecover:
+		s.recover(ctx, monitor)
+	}
+}
+
+// transition is what a check result does to its monitor.
+type transition int
+
+const (
+	transitionNone transition = iota
+	transitionTrip
+	transitionRecover
+)
+
+// nextTransition decides what a result does, given the monitor as it is after
+// the result was recorded: a failure trips an enabled monitor once it reaches
+// FailureThreshold failures in a row, and a success recovers a tripped one.
+func nextTransition(monitor Schemas.Monitor, result Schemas.MonitorResult) transition {
+	switch {
+	case !result.Success && monitor.Enabled && !monitor.Tripped && monitor.ConsecutiveFailures >= monitor.FailureThreshold:
+		return transitionTrip
+	case result.Success && monitor.Tripped:
+		return transitionRecover
+	}
+	return transitionNone
+}
+
+func (s *Scheduler) trip(ctx context.Context, monitor Schemas.Monitor, result Schemas.MonitorResult) {
+	log.Printf("[Monitoring] Monitor %d failed %d times in a row: %s\n", monitor.ID, monitor.ConsecutiveFailures, result.Error)
+
+	o := monitorOutage(monitor)
+	o.Note = o.Title
+	o.Detail = fmt.Sprintf("%s check of %s failed %d times in a row: %s", monitor.Kind, monitor.Target, monitor.ConsecutiveFailures, result.Error)
+	incidentID, err := markDown(ctx, s.DB, o)
+	if err != nil {
+		log.Printf("[Monitoring] Failed to mark service %d down: %v\n", monitor.ServiceID, err)
+	}
+	if err := dbrequests.SetMonitorTripped(ctx, s.DB, monitor.ID, true, incidentID); err != nil {
+		log.Printf("[Monitoring] Failed to save state of monitor %d: %v\n", monitor.ID, err)
+	}
+}
+
+func (s *Scheduler) recover(ctx context.Context, monitor Schemas.Monitor) {
+	log.Printf("[Monitoring] Monitor %d is passing again\n", monitor.ID)
+
+	stillDown, err := dbrequests.OtherTrippedChecks(ctx, s.DB, monitor.ServiceID, monitor.ID, 0)
+	if err != nil {
+		log.Printf("[Monitoring] Failed to check other checks of service %d: %v\n", monitor.ServiceID, err)
+		stillDown = true
+	}
+
+	o := monitorOutage(monitor)
+	o.Note = fmt.Sprintf("%s is passing health checks again", monitor.ServiceName)
+	o.Detail = fmt.Sprintf("%s check of %s is passing again", monitor.Kind, monitor.Target)
+	if err := markUp(ctx, s.DB, o, monitor.IncidentID, stillDown); err != nil {
+		log.Printf("[Monitoring] Failed to mark service %d up: %v\n", monitor.ServiceID, err)
+	}
+	if err := dbrequests.SetMonitorTripped(ctx, s.DB, monitor.ID, false, nil); err != nil {
+		log.Printf("[Monitoring] Failed to save state of monitor %d: %v\n", monitor.ID, err)
+	}
+}
+
+// MonitorReleased lets go of the service a tripped monitor held down once the
+// monitor is disabled, moved, given another failure status or deleted: it
+// resolves the monitor's incident and, unless other checks still fail, sets
+// the service back to operational. The monitor's own state must already be cleared.
+func MonitorReleased(ctx context.Context, db *sql.DB, monitor Schemas.Monitor, reason string) {
+	log.Printf("[Monitoring] Monitor %d %s, releasing service %d\n", monitor.ID, reason, monitor.ServiceID)
+
+	stillDown, err := dbrequests.OtherTrippedChecks(ctx, db, monitor.ServiceID, monitor.ID, 0)
+	if err != nil {
+		log.Printf("[Monitoring] Failed to check other checks of service %d: %v\n", monitor.ServiceID, err)
+		stillDown = true
+	}
+
+	o := monitorOutage(monitor)
+	o.Note = fmt.Sprintf("A health check of %s %s", monitor.ServiceName, reason)
+	o.Detail = fmt.Sprintf("%s check of %s %s", monitor.Kind, monitor.Target, reason)
+	if err := markUp(ctx, db, o, monitor.IncidentID, stillDown); err != nil {
+		log.Printf("[Monitoring] Failed to mark service %d up: %v\n", monitor.ServiceID, err)
+	}
+}
+
+func monitorOutage(monitor Schemas.Monitor) outage {
+	return outage{
+		OrgID:        monitor.OrgID,
+		ServiceID:    monitor.ServiceID,
+		Status:       Schemas.ServiceStatus(monitor.FailureStatus),
+		OpenIncident: monitor.OpenIncident,
+		Title:        fmt.Sprintf("%s is failing health checks", monitor.ServiceName),
+		Source:       "Monitoring",
+	}
+}
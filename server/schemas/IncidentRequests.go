@@ -1,60 +1,147 @@
 package Schemas
 
-import "time"
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
 
+// LinkedServiceIn is a service linked to an incident. Requests give the
+// service in service_id by ID or slug; it is decoded into Service and
+// resolved to ServiceID before the link is stored.
 type LinkedServiceIn struct {
-	ServiceID *int32 `json:"service_id"`
-	Name      string `json:"name"`
+	ServiceID *int32     `json:"service_id"`
+	Service   ServiceRef `json:"-"`
+	Name      string     `json:"name"`
+	Impact    Impact     `json:"impact,omitempty"`
+}
+
+func (l *LinkedServiceIn) UnmarshalJSON(data []byte) error {
+	type plain LinkedServiceIn
+	var in struct {
+		plain
+		Service ServiceRef `json:"service_id"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*l = LinkedServiceIn(in.plain)
+	l.Service = in.Service
+	return nil
 }
 
 type IncidentRequest struct {
 	Title          string            `json:"title"`
 	Description    string            `json:"description"`
-	Status         string            `json:"status"`
+	Status         IncidentStatus    `json:"status"`
 	StartedAt      string            `json:"started_at"`
 	LinkedServices []LinkedServiceIn `json:"linked_services"`
+	Labels         Labels            `json:"labels"`
+	Severity       string            `json:"severity"`
 }
 
 type Incident struct {
-	ID             string     `json:"id"`
-	Title          string     `json:"title"`
-	Description    string     `json:"description", omitempty`
-	Status         string     `json:"status"`
-	StartedAt      time.Time  `json:"started_at"`
-	ResolvedAt     *time.Time `json:"resolved_at", omitempty`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
-	CreatedByClerk string     `json:"created_by_clerk"`
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Description    string         `json:"description,omitempty"`
+	Status         IncidentStatus `json:"status"`
+	StartedAt      time.Time      `json:"started_at"`
+	ResolvedAt     *time.Time     `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	CreatedByClerk string         `json:"created_by_clerk"`
+	Version        int            `json:"version"`
+	MergedInto     *string        `json:"merged_into,omitempty"`
+	Labels         Labels         `json:"labels"`
+	Severity       string         `json:"severity,omitempty"`
 }
 
 type EditInstance struct {
 	ID             string            `json:"id"`
 	Title          string            `json:"title"`
-	Description    string            `json:"description", omitempty`
-	Status         string            `json:"status"`
+	Description    string            `json:"description,omitempty"`
+	Status         IncidentStatus    `json:"status"`
 	StartedAt      time.Time         `json:"started_at"`
 	LinkedServices []LinkedServiceIn `json:"linked_services"`
+	Version        *int              `json:"version,omitempty"`
+	Labels         Labels            `json:"labels,omitempty"`
+	Severity       string            `json:"severity,omitempty"`
 }
 
 type IncidentTitles struct {
-	ID        string `json:"id"`
-	Title     string `json:"title"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_at"`
+	ID        string         `json:"id"`
+	Title     string         `json:"title"`
+	Status    IncidentStatus `json:"status"`
+	CreatedAt string         `json:"created_at"`
+	Labels    Labels         `json:"labels"`
+	Severity  string         `json:"severity,omitempty"`
 }
 
 type IncidentUpdate struct {
 	Title          string   `json:"title"`
-	Description    string   `json:description""`
+	Description    string   `json:"description"`
 	LinkedServices []string `json:"linked_services"`
 }
 
 type IncidentUpdateData struct {
-	ID             string    `json:"id"`
-	IncidentId     string    `json:"incident_id"`
-	Message        string    `json:"message"`
-	Status         string    `json:"status"`
-	CreatedAt      time.Time `json:"created_at"`
-	FullName       *string   `json:"full_name"`
-	CreatedByClerk string    `json:"created_by_clerk"`
+	ID             string         `json:"id"`
+	IncidentId     string         `json:"incident_id"`
+	Message        string         `json:"message"`
+	Status         IncidentStatus `json:"status"`
+	CreatedAt      time.Time      `json:"created_at"`
+	FullName       *string        `json:"full_name"`
+	CreatedByClerk string         `json:"created_by_clerk"`
+	Visibility     string         `json:"visibility"`
+}
+
+// IncidentPatch is a JSON Merge Patch for an incident: nil fields are left
+// untouched. LinkedServices replaces the whole set of links, while
+// AddLinkedServices and RemoveLinkedServices change individual links and
+// ServiceImpacts changes the impact of existing links. Services are given by
+// ID or slug.
+type IncidentPatch struct {
+	Title                *string               `json:"title"`
+	Description          *string               `json:"description"`
+	Status               *IncidentStatus       `json:"status"`
+	StartedAt            *time.Time            `json:"started_at"`
+	LinkedServices       *[]LinkedServiceIn    `json:"linked_services"`
+	AddLinkedServices    []ServiceRef          `json:"add_linked_services"`
+	RemoveLinkedServices []ServiceRef          `json:"remove_linked_services"`
+	ServiceImpacts       map[ServiceRef]Impact `json:"service_impacts"`
+	Labels               LabelsPatch           `json:"labels"`
+	Severity             *string               `json:"severity"`
+	Version              *int                  `json:"version"`
+}
+
+// MergeIncidentsRequest merges every source incident into the target.
+type MergeIncidentsRequest struct {
+	TargetID  string   `json:"target_id"`
+	SourceIDs []string `json:"source_ids"`
+}
+
+// TimelineNote is the message of timeline entries written by the server
+// itself rather than by an edit.
+type TimelineNote struct {
+	Note string `json:"note"`
+}
+
+// Timeline entries are either shown publicly or kept to org members.
+const (
+	VisibilityPublic   = "public"
+	VisibilityInternal = "internal"
+)
+
+// IncidentNoteRequest adds a free-text entry to an incident's timeline.
+// Visibility defaults to internal.
+type IncidentNoteRequest struct {
+	Note       string `json:"note"`
+	Visibility string `json:"visibility"`
+}
+
+// IncidentSeverities lists the allowed severities, most severe first. An
+// incident may also have no severity.
+var IncidentSeverities = []string{"critical", "major", "minor"}
+
+func ValidSeverity(severity string) bool {
+	return severity == "" || slices.Contains(IncidentSeverities, severity)
 }
@@ -0,0 +1,17 @@
+package Schemas
+
+import "time"
+
+// Attachment is the metadata of a file attached to an incident, optionally
+// tied to one of its timeline entries. The bytes live in the blob store.
+type Attachment struct {
+	ID             string    `json:"id"`
+	IncidentID     string    `json:"incident_id"`
+	UpdateID       *string   `json:"update_id,omitempty"`
+	FileName       string    `json:"file_name"`
+	ContentType    string    `json:"content_type"`
+	SizeBytes      int64     `json:"size_bytes"`
+	StorageKey     string    `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	CreatedByClerk string    `json:"created_by_clerk"`
+}
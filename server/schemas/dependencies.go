@@ -0,0 +1,33 @@
+package Schemas
+
+// ServiceDependency says ServiceID depends on DependsOnID.
+type ServiceDependency struct {
+	ServiceID   int `json:"service_id"`
+	DependsOnID int `json:"depends_on_id"`
+}
+
+// ServiceDependenciesRequest replaces everything a service depends on.
+type ServiceDependenciesRequest struct {
+	DependsOn []int `json:"depends_on"`
+}
+
+type ServiceGraph struct {
+	Services     []Service           `json:"services"`
+	Dependencies []ServiceDependency `json:"dependencies"`
+}
+
+// ImpactedService is a service that depends, directly or through other
+// services, on one an incident affects. Via is the chain of service IDs from
+// the affected service to the one this service depends on directly.
+type ImpactedService struct {
+	Service
+	Via []int `json:"via"`
+}
+
+// BlastRadius is what an incident reaches: the services linked to it and
+// those potentially impacted through dependencies.
+type BlastRadius struct {
+	IncidentID          string            `json:"incident_id"`
+	Affected            []Service         `json:"affected"`
+	PotentiallyImpacted []ImpactedService `json:"potentially_impacted"`
+}
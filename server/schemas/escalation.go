@@ -0,0 +1,66 @@
+package Schemas
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const maxEscalationSteps = 10
+
+// EscalationStep notifies Notify (e.g. "responder", "team-lead", "everyone")
+// once an incident has gone AfterMinutes without being acknowledged. ID is
+// assigned by the server and stays with the step across policy edits; send
+// it back to keep a step, leave it out to add one.
+type EscalationStep struct {
+	ID           string `json:"id"`
+	AfterMinutes int    `json:"after_minutes"`
+	Notify       string `json:"notify"`
+}
+
+// EscalationPolicy is an org's escalation chain, evaluated against incidents
+// still in "investigating".
+type EscalationPolicy struct {
+	Steps     []EscalationStep `json:"steps"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+func (p EscalationPolicy) Validate() error {
+	if len(p.Steps) == 0 {
+		return errors.New("at least one step is required")
+	}
+	if len(p.Steps) > maxEscalationSteps {
+		return fmt.Errorf("at most %d steps are allowed", maxEscalationSteps)
+	}
+	ids := map[string]bool{}
+	for i, step := range p.Steps {
+		if step.ID != "" {
+			if ids[step.ID] {
+				return fmt.Errorf("step %d: id %s is used twice", i+1, step.ID)
+			}
+			ids[step.ID] = true
+		}
+		if step.Notify == "" {
+			return fmt.Errorf("step %d: notify is required", i+1)
+		}
+		if step.AfterMinutes < 0 {
+			return fmt.Errorf("step %d: after_minutes cannot be negative", i+1)
+		}
+		if i > 0 && step.AfterMinutes < p.Steps[i-1].AfterMinutes {
+			return fmt.Errorf("step %d: steps must be ordered by after_minutes", i+1)
+		}
+	}
+	return nil
+}
+
+// EscalationEvent is emitted each time an escalation step fires. Step is the
+// step's position in the policy at the time.
+type EscalationEvent struct {
+	IncidentID   string    `json:"incident_id"`
+	Title        string    `json:"title"`
+	Step         int       `json:"step"`
+	StepID       string    `json:"step_id"`
+	AfterMinutes int       `json:"after_minutes"`
+	Notify       string    `json:"notify"`
+	EscalatedAt  time.Time `json:"escalated_at"`
+}
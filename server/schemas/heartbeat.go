@@ -0,0 +1,59 @@
+package Schemas
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultHeartbeatGrace = 300
+	minHeartbeatGrace     = 30
+)
+
+// HeartbeatRequest configures a heartbeat. GraceSeconds is how long the
+// service may go without a ping before it is marked down.
+type HeartbeatRequest struct {
+	Service       ServiceRef `json:"service_id" binding:"required"`
+	Name          string     `json:"name"`
+	GraceSeconds  int        `json:"grace_seconds"`
+	FailureStatus Impact     `json:"failure_status"`
+	OpenIncident  *bool      `json:"open_incident"`
+	// ServiceID is Service resolved within the org.
+	ServiceID int `json:"-"`
+}
+
+// Normalize fills in defaults and validates the request.
+func (r *HeartbeatRequest) Normalize() error {
+	if r.GraceSeconds == 0 {
+		r.GraceSeconds = defaultHeartbeatGrace
+	}
+	if r.FailureStatus == "" {
+		r.FailureStatus = Impact(ServiceMajorOutage)
+	}
+	if r.OpenIncident == nil {
+		open := true
+		r.OpenIncident = &open
+	}
+
+	if r.GraceSeconds < minHeartbeatGrace {
+		return errors.New("grace_seconds must be at least 30")
+	}
+	return nil
+}
+
+// Heartbeat is a stored heartbeat. Token is only set in the response that
+// creates it; afterwards only its hash is kept.
+type Heartbeat struct {
+	ID            int        `json:"id"`
+	ServiceID     int        `json:"service_id"`
+	Name          string     `json:"name"`
+	Token         string     `json:"token,omitempty"`
+	GraceSeconds  int        `json:"grace_seconds"`
+	FailureStatus Impact     `json:"failure_status"`
+	OpenIncident  bool       `json:"open_incident"`
+	LastPingAt    *time.Time `json:"last_ping_at"`
+	Tripped       bool       `json:"tripped"`
+	IncidentID    *string    `json:"incident_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	OrgID         string     `json:"-"`
+}
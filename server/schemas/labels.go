@@ -0,0 +1,71 @@
+package Schemas
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+var labelKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]{0,61}[A-Za-z0-9])?$`)
+
+const maxLabelValueLength = 255
+
+// Labels are free-form key/value tags such as team=payments, stored as a
+// jsonb object on incidents and services.
+type Labels map[string]string
+
+// Validate checks keys and values are short and filter-friendly.
+func (l Labels) Validate() error {
+	for key, value := range l {
+		if !labelKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid label key %q: use up to 63 letters, digits, '.', '_', '/' or '-'", key)
+		}
+		if len(value) > maxLabelValueLength {
+			return fmt.Errorf("label %q value is longer than %d characters", key, maxLabelValueLength)
+		}
+	}
+	return nil
+}
+
+// Value stores nil labels as SQL NULL so queries can COALESCE to the
+// existing labels.
+func (l Labels) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	// lib/pq sends []byte as bytea, so hand jsonb columns a string.
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+func (l *Labels) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*l = Labels{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("cannot scan %T into Labels", src)
+	}
+}
+
+// LabelsPatch is a JSON Merge Patch on labels: a null value removes the key.
+type LabelsPatch map[string]*string
+
+// Split returns the labels to set and the keys to remove.
+func (p LabelsPatch) Split() (Labels, []string) {
+	set := Labels{}
+	remove := []string{}
+	for key, value := range p {
+		if value == nil {
+			remove = append(remove, key)
+		} else {
+			set[key] = *value
+		}
+	}
+	return set, remove
+}
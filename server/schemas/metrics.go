@@ -0,0 +1,104 @@
+package Schemas
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	MetricResolutionMinute = "minute"
+	MetricResolutionHour   = "hour"
+	MetricResolutionDay    = "day"
+
+	// MaxMetricPoints caps the points accepted in one push.
+	MaxMetricPoints = 1000
+	// maxMetricClockSkew is how far in the future a point may be stamped.
+	maxMetricClockSkew = 5 * time.Minute
+)
+
+// MetricResolutions lists the rollup resolutions, finest first.
+var MetricResolutions = []string{MetricResolutionMinute, MetricResolutionHour, MetricResolutionDay}
+
+// MetricRetention is how long rollups of each resolution are kept. Day
+// rollups are kept longest, so no point older than theirs is accepted.
+var MetricRetention = map[string]time.Duration{
+	MetricResolutionMinute: 7 * 24 * time.Hour,
+	MetricResolutionHour:   90 * 24 * time.Hour,
+	MetricResolutionDay:    400 * 24 * time.Hour,
+}
+
+var metricNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_.]{0,63}$`)
+
+// MetricPoint is one measurement. A zero Timestamp means now.
+type MetricPoint struct {
+	Metric    string    `json:"metric" binding:"required"`
+	Value     *float64  `json:"value" binding:"required"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MetricBatch is the body of a metric push.
+type MetricBatch struct {
+	Points []MetricPoint `json:"points" binding:"required,min=1,dive"`
+}
+
+// Normalize stamps points without a timestamp with now and validates names,
+// batch size and timestamps. Points older than every rollup's retention are
+// refused rather than stored only to be pruned.
+func (b *MetricBatch) Normalize(now time.Time) error {
+	if len(b.Points) > MaxMetricPoints {
+		return fmt.Errorf("at most %d points can be sent at once", MaxMetricPoints)
+	}
+	for i := range b.Points {
+		point := &b.Points[i]
+		if !metricNamePattern.MatchString(point.Metric) {
+			return fmt.Errorf("invalid metric name %q: use up to 64 lowercase letters, digits, '_' or '.', starting with a letter", point.Metric)
+		}
+		if point.Timestamp.IsZero() {
+			point.Timestamp = now
+		}
+		if point.Timestamp.After(now.Add(maxMetricClockSkew)) {
+			return errors.New("timestamps cannot be in the future")
+		}
+		if retention := MetricRetention[MetricResolutionDay]; point.Timestamp.Before(now.Add(-retention)) {
+			return fmt.Errorf("timestamps cannot be more than %d days old", int(retention.Hours()/24))
+		}
+	}
+	return nil
+}
+
+// MetricBucket is the rollup of one metric over one bucket.
+type MetricBucket struct {
+	Bucket time.Time `json:"bucket"`
+	Count  int64     `json:"count"`
+	Sum    float64   `json:"sum"`
+	Min    float64   `json:"min"`
+	Max    float64   `json:"max"`
+	Avg    float64   `json:"avg"`
+}
+
+// MetricSeries is a metric of a service over a time range.
+type MetricSeries struct {
+	ServiceID  int            `json:"service_id"`
+	Metric     string         `json:"metric"`
+	Resolution string         `json:"resolution"`
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	Points     []MetricBucket `json:"points"`
+}
+
+// IngestKeyRequest names a new ingest key.
+type IngestKeyRequest struct {
+	Name string `json:"name"`
+}
+
+// IngestKey authenticates metric pushes for an org. Token is only set in the
+// response that creates it; afterwards only its hash is kept.
+type IngestKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Token      string     `json:"token,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
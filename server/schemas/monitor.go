@@ -0,0 +1,206 @@
+package Schemas
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MonitorKinds are the synthetic checks the server can run.
+var MonitorKinds = []string{"http", "tcp", "dns"}
+
+const (
+	defaultMonitorInterval  = 60
+	minMonitorInterval      = 10
+	defaultMonitorTimeout   = 10
+	defaultFailureThreshold = 3
+)
+
+// MonitorRequest configures a monitor. Target is a URL for http, host:port
+// for tcp and a host name for dns. ExpectedStatus 0 accepts any 2xx.
+type MonitorRequest struct {
+	Service          ServiceRef `json:"service_id" binding:"required"`
+	Kind             string     `json:"kind" binding:"required"`
+	Target           string     `json:"target" binding:"required"`
+	ExpectedStatus   int        `json:"expected_status"`
+	BodyContains     string     `json:"body_contains"`
+	IntervalSeconds  int        `json:"interval_seconds"`
+	TimeoutSeconds   int        `json:"timeout_seconds"`
+	FailureThreshold int        `json:"failure_threshold"`
+	FailureStatus    Impact     `json:"failure_status"`
+	OpenIncident     bool       `json:"open_incident"`
+	Enabled          *bool      `json:"enabled"`
+	// ServiceID is Service resolved within the org.
+	ServiceID int `json:"-"`
+}
+
+// Normalize fills in defaults and validates the request.
+func (r *MonitorRequest) Normalize() error {
+	if !slices.Contains(MonitorKinds, r.Kind) {
+		return errors.New("kind must be http, tcp or dns")
+	}
+	if err := validateTarget(r.Kind, r.Target); err != nil {
+		return err
+	}
+	if r.Kind != "http" && (r.ExpectedStatus != 0 || r.BodyContains != "") {
+		return errors.New("expected_status and body_contains only apply to http monitors")
+	}
+	if r.ExpectedStatus != 0 && (r.ExpectedStatus < 100 || r.ExpectedStatus > 599) {
+		return errors.New("expected_status must be an HTTP status code")
+	}
+
+	if r.IntervalSeconds == 0 {
+		r.IntervalSeconds = defaultMonitorInterval
+	}
+	if r.TimeoutSeconds == 0 {
+		r.TimeoutSeconds = min(defaultMonitorTimeout, r.IntervalSeconds)
+	}
+	if r.FailureThreshold == 0 {
+		r.FailureThreshold = defaultFailureThreshold
+	}
+	if r.FailureStatus == "" {
+		r.FailureStatus = Impact(ServiceMajorOutage)
+	}
+	if r.Enabled == nil {
+		enabled := true
+		r.Enabled = &enabled
+	}
+
+	if r.IntervalSeconds < minMonitorInterval {
+		return fmt.Errorf("interval_seconds must be at least %d", minMonitorInterval)
+	}
+	if r.TimeoutSeconds < 1 || r.TimeoutSeconds > r.IntervalSeconds {
+		return errors.New("timeout_seconds must be between 1 and interval_seconds")
+	}
+	if r.FailureThreshold < 1 {
+		return errors.New("failure_threshold must be at least 1")
+	}
+	return nil
+}
+
+var hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*\.?$`)
+
+// validateTarget checks the shape of a target for its kind and refuses
+// addresses monitors must not reach. Names are checked again when the probe
+// connects, since they may resolve anywhere.
+func validateTarget(kind, target string) error {
+	var host string
+	switch kind {
+	case "http":
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			return errors.New("target must be an absolute http or https URL")
+		}
+		if port := u.Port(); port != "" && !validPort(port) {
+			return errors.New("target has an invalid port")
+		}
+		host = u.Hostname()
+	case "tcp":
+		h, port, err := net.SplitHostPort(target)
+		if err != nil || h == "" || !validPort(port) {
+			return errors.New("target must be host:port for tcp monitors")
+		}
+		host = h
+	case "dns":
+		host = target
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !PublicAddress(addr) {
+			return errors.New("target must not be a loopback, private, link-local or otherwise internal address")
+		}
+		return nil
+	}
+	if len(host) > 253 || !hostnamePattern.MatchString(host) {
+		return errors.New("target has an invalid host name")
+	}
+	name := strings.ToLower(strings.TrimSuffix(host, "."))
+	if name == "localhost" || strings.HasSuffix(name, ".localhost") {
+		return errors.New("target must not be a loopback, private, link-local or otherwise internal address")
+	}
+	return nil
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+// internalPrefixes are the ranges beyond those the netip predicates cover that
+// monitors must not reach: shared address space, IETF protocol assignments,
+// benchmarking, reserved space and the IPv6 discard and documentation ranges.
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// nat64Prefix embeds an IPv4 address in its last four bytes.
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// PublicAddress reports whether monitors may connect to addr. Loopback,
+// private, link-local (which holds cloud metadata endpoints), multicast,
+// unspecified and reserved addresses are refused, including when wrapped in
+// IPv4-mapped or NAT64 IPv6 addresses.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if nat64Prefix.Contains(addr) {
+		b := addr.As16()
+		return PublicAddress(netip.AddrFrom4([4]byte(b[12:])))
+	}
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Monitor is a stored monitor with its current state. Tripped is set once
+// FailureThreshold consecutive checks failed and cleared by the next success.
+type Monitor struct {
+	ID                  int        `json:"id"`
+	ServiceID           int        `json:"service_id"`
+	Kind                string     `json:"kind"`
+	Target              string     `json:"target"`
+	ExpectedStatus      int        `json:"expected_status,omitempty"`
+	BodyContains        string     `json:"body_contains,omitempty"`
+	IntervalSeconds     int        `json:"interval_seconds"`
+	TimeoutSeconds      int        `json:"timeout_seconds"`
+	FailureThreshold    int        `json:"failure_threshold"`
+	FailureStatus       Impact     `json:"failure_status"`
+	OpenIncident        bool       `json:"open_incident"`
+	Enabled             bool       `json:"enabled"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Tripped             bool       `json:"tripped"`
+	IncidentID          *string    `json:"incident_id"`
+	LastCheckedAt       *time.Time `json:"last_checked_at"`
+	OrgID               string     `json:"-"`
+	ServiceName         string     `json:"-"`
+}
+
+type MonitorResult struct {
+	ID        int64     `json:"id"`
+	MonitorID int       `json:"monitor_id"`
+	CheckedAt time.Time `json:"checked_at"`
+	Success   bool      `json:"success"`
+	LatencyMs float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+}
@@ -0,0 +1,99 @@
+package Schemas
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StatusPageSettings is an org's public status page configuration. Slug is
+// the Clerk org slug the page is served under.
+type StatusPageSettings struct {
+	Slug    string `json:"slug"`
+	Enabled bool   `json:"enabled"`
+}
+
+type StatusPageRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// PublicService is the part of a service shown on the public status page.
+type PublicService struct {
+	ID          int           `json:"id"`
+	Slug        string        `json:"slug"`
+	Name        string        `json:"name"`
+	Status      ServiceStatus `json:"status"`
+	Description string        `json:"description"`
+	PublicURL   string        `json:"public_url,omitempty"`
+	GroupID     *int          `json:"group_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type PublicServiceGroup struct {
+	ID       int           `json:"id"`
+	Name     string        `json:"name"`
+	Position int           `json:"position"`
+	Status   ServiceStatus `json:"status"`
+}
+
+// PublicAffectedService is a visible service linked to a public incident.
+type PublicAffectedService struct {
+	ID     int    `json:"id"`
+	Slug   string `json:"slug"`
+	Name   string `json:"name"`
+	Impact string `json:"impact"`
+}
+
+// PublicUpdate is a public timeline entry with its message rendered as text.
+type PublicUpdate struct {
+	ID        string         `json:"id"`
+	Status    IncidentStatus `json:"status"`
+	Body      string         `json:"body"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type PublicIncident struct {
+	ID               string                  `json:"id"`
+	Title            string                  `json:"title"`
+	Description      string                  `json:"description"`
+	Status           IncidentStatus          `json:"status"`
+	StartedAt        time.Time               `json:"started_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+	ResolvedAt       *time.Time              `json:"resolved_at"`
+	AffectedServices []PublicAffectedService `json:"affected_services"`
+	Updates          []PublicUpdate          `json:"updates"`
+}
+
+// StatusPageSummary is everything a public status page shows. Status is the
+// worst status among the visible services.
+type StatusPageSummary struct {
+	Page                StatusPageSettings   `json:"page"`
+	Status              ServiceStatus        `json:"status"`
+	Groups              []PublicServiceGroup `json:"groups"`
+	Services            []PublicService      `json:"services"`
+	Incidents           []PublicIncident     `json:"incidents"`
+	UpcomingMaintenance []PublicIncident     `json:"upcoming_maintenance"`
+	GeneratedAt         time.Time            `json:"generated_at"`
+}
+
+// UpdateText renders a timeline message as plain text. Messages are JSON: a
+// TimelineNote, or an IncidentUpdate written on edits, whose description is
+// the most useful part.
+func UpdateText(message string) string {
+	var parsed struct {
+		Note        string `json:"note"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(message), &parsed); err != nil {
+		return message
+	}
+	switch {
+	case parsed.Note != "":
+		return parsed.Note
+	case parsed.Description != "":
+		return parsed.Description
+	default:
+		return parsed.Title
+	}
+}
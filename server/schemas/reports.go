@@ -0,0 +1,59 @@
+package Schemas
+
+import "time"
+
+// IncidentTimings are the SLA milestones of one incident, derived from its
+// timeline: acknowledged when it first leaves "investigating", mitigated when
+// it first reaches "monitoring" (or resolved), and resolved at resolved_at.
+// Durations are seconds since started_at and are nil until reached.
+type IncidentTimings struct {
+	IncidentID               string     `json:"incident_id"`
+	Severity                 string     `json:"severity,omitempty"`
+	ServiceIDs               []int64    `json:"service_ids,omitempty"`
+	StartedAt                time.Time  `json:"started_at"`
+	AcknowledgedAt           *time.Time `json:"acknowledged_at"`
+	MitigatedAt              *time.Time `json:"mitigated_at"`
+	ResolvedAt               *time.Time `json:"resolved_at"`
+	TimeToAcknowledgeSeconds *float64   `json:"time_to_acknowledge_seconds"`
+	TimeToMitigateSeconds    *float64   `json:"time_to_mitigate_seconds"`
+	TimeToResolveSeconds     *float64   `json:"time_to_resolve_seconds"`
+}
+
+// IncidentMetrics aggregates timings over a group of incidents. Means only
+// count incidents that reached the milestone.
+type IncidentMetrics struct {
+	Incidents    int      `json:"incidents"`
+	Acknowledged int      `json:"acknowledged"`
+	Mitigated    int      `json:"mitigated"`
+	Resolved     int      `json:"resolved"`
+	MTTASeconds  *float64 `json:"mtta_seconds"`
+	MTTMSeconds  *float64 `json:"mttm_seconds"`
+	MTTRSeconds  *float64 `json:"mttr_seconds"`
+}
+
+type ServiceIncidentMetrics struct {
+	ServiceID   int    `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	IncidentMetrics
+}
+
+type SeverityIncidentMetrics struct {
+	Severity string `json:"severity"`
+	IncidentMetrics
+}
+
+type MonthIncidentMetrics struct {
+	Month string `json:"month"`
+	IncidentMetrics
+}
+
+// IncidentMetricsReport is the org-level MTTA/MTTR report over incidents that
+// started in [From, To).
+type IncidentMetricsReport struct {
+	From       time.Time                 `json:"from"`
+	To         time.Time                 `json:"to"`
+	Overall    IncidentMetrics           `json:"overall"`
+	BySeverity []SeverityIncidentMetrics `json:"by_severity"`
+	ByService  []ServiceIncidentMetrics  `json:"by_service"`
+	ByMonth    []MonthIncidentMetrics    `json:"by_month"`
+}
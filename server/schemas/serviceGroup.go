@@ -0,0 +1,34 @@
+package Schemas
+
+type ServiceGroupRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Position int    `json:"position"`
+}
+
+// ServiceGroup is a named set of services. Status is the worst status among
+// its services, or operational when it has none.
+type ServiceGroup struct {
+	ID       int           `json:"id"`
+	Name     string        `json:"name"`
+	Position int           `json:"position"`
+	Status   ServiceStatus `json:"status"`
+	Services []Service     `json:"services"`
+}
+
+// ServiceTree is the org's services nested under their groups, groups in
+// display order.
+type ServiceTree struct {
+	Groups    []ServiceGroup `json:"groups"`
+	Ungrouped []Service      `json:"ungrouped"`
+}
+
+// AggregateStatus returns the worst status among services.
+func AggregateStatus(services []Service) ServiceStatus {
+	status := ServiceOperational
+	for _, service := range services {
+		if service.Status.Worse(status) {
+			status = service.Status
+		}
+	}
+	return status
+}
@@ -0,0 +1,88 @@
+package Schemas
+
+import (
+	"fmt"
+	"net/url"
+	"unicode/utf8"
+)
+
+const (
+	maxDescriptionLength = 2000
+	maxMetadataLength    = 255
+)
+
+// ServiceMetadata describes a service for responders and the public status
+// page. Fields are pointers so a PUT or patch can leave them untouched; stored
+// services always have every field set.
+type ServiceMetadata struct {
+	Description *string `json:"description"`
+	PublicURL   *string `json:"public_url"`
+	// RunbookURL links to the service's documentation or runbook.
+	RunbookURL *string `json:"runbook_url"`
+	OwnerTeam  *string `json:"owner_team"`
+	Contact    *string `json:"contact"`
+	// DisplayOrder sorts services ascending; ties fall back to id.
+	DisplayOrder *int `json:"display_order"`
+	// Hidden services are left off the public status page.
+	Hidden *bool `json:"hidden"`
+}
+
+// MetadataFields are the JSON members of ServiceMetadata.
+var MetadataFields = []string{"description", "public_url", "runbook_url", "owner_team", "contact", "display_order", "hidden"}
+
+// Validate checks lengths and that links are absolute http(s) URLs. Fields
+// are checked in a fixed order, so the same request always reports the same
+// error.
+func (m ServiceMetadata) Validate() error {
+	texts := []struct {
+		field string
+		value *string
+		max   int
+		link  bool
+	}{
+		{"description", m.Description, maxDescriptionLength, false},
+		{"public_url", m.PublicURL, maxMetadataLength, true},
+		{"runbook_url", m.RunbookURL, maxMetadataLength, true},
+		{"owner_team", m.OwnerTeam, maxMetadataLength, false},
+		{"contact", m.Contact, maxMetadataLength, false},
+	}
+	for _, text := range texts {
+		if text.value == nil {
+			continue
+		}
+		if utf8.RuneCountInString(*text.value) > text.max {
+			return fmt.Errorf("%s is longer than %d characters", text.field, text.max)
+		}
+		if !text.link || *text.value == "" {
+			continue
+		}
+		u, err := url.Parse(*text.value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s must be an http or https URL", text.field)
+		}
+	}
+	return nil
+}
+
+// Clear resets the named fields to their empty values, which is what a null
+// member in a merge patch means for metadata.
+func (m *ServiceMetadata) Clear(fields ...string) {
+	for _, field := range fields {
+		switch field {
+		case "description":
+			m.Description = new(string)
+		case "public_url":
+			m.PublicURL = new(string)
+		case "runbook_url":
+			m.RunbookURL = new(string)
+		case "owner_team":
+			m.OwnerTeam = new(string)
+		case "contact":
+			m.Contact = new(string)
+		case "display_order":
+			m.DisplayOrder = new(int)
+		case "hidden":
+			m.Hidden = new(bool)
+		}
+	}
+}
@@ -1,21 +1,99 @@
 package Schemas
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ServiceRef names one of the org's services by ID or slug. It decodes from
+// a JSON number or string; a number is kept in its decimal form.
+type ServiceRef string
+
+func (r *ServiceRef) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var id int
+	if err := json.Unmarshal(data, &id); err == nil {
+		*r = ServiceRef(strconv.Itoa(id))
+		return nil
+	}
+	var ref string
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return errors.New("a service must be given by its ID or slug")
+	}
+	*r = ServiceRef(ref)
+	return nil
+}
 
 type ServiceRequest struct {
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	Name string `json:"name"`
+	// Slug is generated from the name when empty.
+	Slug       string        `json:"slug"`
+	Status     ServiceStatus `json:"status"`
+	Labels     Labels        `json:"labels"`
+	StatusMode string        `json:"status_mode"`
+	GroupID    *int          `json:"group_id"`
+	ServiceMetadata
 }
 
 type Service struct {
-	ID     int    `json:"id"`
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Slug is unique within the org; an empty slug in a PUT keeps the stored
+	// one.
+	Slug    string        `json:"slug"`
+	Status  ServiceStatus `json:"status"`
+	Version int           `json:"version"`
+	Labels  Labels        `json:"labels"`
+	// StatusMode is "manual" or "auto"; auto services take their status from
+	// linked unresolved incidents.
+	StatusMode string `json:"status_mode,omitempty"`
+	GroupID    *int   `json:"group_id"`
+	// Impact is only set when the service is listed as affected by an incident.
+	Impact string `json:"impact,omitempty"`
+	ServiceMetadata
 }
 
 type ServiceResponse struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Status    string    `json:"status"`
+	Service
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// ServicePatch is a JSON Merge Patch for a service: nil fields are left
+// untouched.
+type ServicePatch struct {
+	Name       *string        `json:"name"`
+	Slug       *string        `json:"slug"`
+	Status     *ServiceStatus `json:"status"`
+	StatusMode *string        `json:"status_mode"`
+	// GroupID 0 moves the service out of its group; a null member in the
+	// patch is turned into 0.
+	GroupID *int        `json:"group_id"`
+	Labels  LabelsPatch `json:"labels"`
+	Version *int        `json:"version"`
+	ServiceMetadata
+}
+
+// ImpactLevels are how badly an incident affects a linked service, mildest
+// first. They are the degraded service statuses.
+var ImpactLevels = statusStrings([]ServiceStatus{ServiceDegraded, ServicePartialOutage, ServiceMajorOutage})
+
+// DefaultImpact is used for links created without an explicit impact.
+const DefaultImpact = "partial outage"
+
+func ValidImpact(impact string) bool {
+	return slices.Contains(ImpactLevels, impact)
+}
+
+const (
+	StatusModeManual = "manual"
+	StatusModeAuto   = "auto"
+)
+
+func ValidStatusMode(mode string) bool {
+	return mode == "" || mode == StatusModeManual || mode == StatusModeAuto
+}
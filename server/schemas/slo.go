@@ -0,0 +1,127 @@
+package Schemas
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+const (
+	defaultSLOWindowDays = 30
+	maxSLOWindowDays     = 365
+)
+
+// DefaultSLOAlertThresholds are the percentages of error budget consumed at
+// which a burn event is sent when none are configured.
+var DefaultSLOAlertThresholds = []float64{50, 75, 90, 100}
+
+// Burn rate windows, as named in SLOBurnEvent.Window.
+const (
+	SLOBurnWindow1h  = "1h"
+	SLOBurnWindow24h = "24h"
+)
+
+// SLOBurnWindows lists the windows burn rates are alerted on.
+var SLOBurnWindows = []string{SLOBurnWindow1h, SLOBurnWindow24h}
+
+// Default burn rates that trigger an alert: at 14.4 an hour spends 2% of a
+// 30-day budget, at 6 a day spends 20% of it.
+const (
+	DefaultSLOBurnRateThreshold1h  = 14.4
+	DefaultSLOBurnRateThreshold24h = 6
+)
+
+// SLORequest defines an availability objective for a service: Target percent
+// of the last WindowDays days must count as up. The burn rate thresholds
+// alert on fast burn before much budget is gone; 0 turns one off.
+type SLORequest struct {
+	Service              ServiceRef `json:"service_id" binding:"required"`
+	Target               float64    `json:"target" binding:"required"`
+	WindowDays           int        `json:"window_days"`
+	AlertThresholds      []float64  `json:"alert_thresholds"`
+	BurnRateThreshold1h  *float64   `json:"burn_rate_threshold_1h"`
+	BurnRateThreshold24h *float64   `json:"burn_rate_threshold_24h"`
+	// ServiceID is Service resolved within the org.
+	ServiceID int `json:"-"`
+}
+
+// Normalize fills in defaults and validates the request. Thresholds are
+// sorted and deduplicated.
+func (r *SLORequest) Normalize() error {
+	if r.WindowDays == 0 {
+		r.WindowDays = defaultSLOWindowDays
+	}
+	if r.AlertThresholds == nil {
+		r.AlertThresholds = slices.Clone(DefaultSLOAlertThresholds)
+	}
+	if r.BurnRateThreshold1h == nil {
+		threshold := float64(DefaultSLOBurnRateThreshold1h)
+		r.BurnRateThreshold1h = &threshold
+	}
+	if r.BurnRateThreshold24h == nil {
+		threshold := float64(DefaultSLOBurnRateThreshold24h)
+		r.BurnRateThreshold24h = &threshold
+	}
+
+	if r.Target <= 0 || r.Target >= 100 {
+		return errors.New("target must be a percentage between 0 and 100, e.g. 99.9")
+	}
+	if r.WindowDays < 1 || r.WindowDays > maxSLOWindowDays {
+		return errors.New("window_days must be between 1 and 365")
+	}
+	for _, threshold := range r.AlertThresholds {
+		if threshold <= 0 || threshold > 100 {
+			return errors.New("alert_thresholds must be percentages of budget consumed between 0 and 100")
+		}
+	}
+	if *r.BurnRateThreshold1h < 0 || *r.BurnRateThreshold24h < 0 {
+		return errors.New("burn rate thresholds cannot be negative")
+	}
+	slices.Sort(r.AlertThresholds)
+	r.AlertThresholds = slices.Compact(r.AlertThresholds)
+	return nil
+}
+
+// SLO is a stored objective. AlertedThreshold is the highest threshold a burn
+// event has been sent for; it drops again as the budget recovers. The
+// BurnAlerted flags are set while a burn rate alert is firing.
+type SLO struct {
+	ID                   int       `json:"id"`
+	ServiceID            int       `json:"service_id"`
+	Target               float64   `json:"target"`
+	WindowDays           int       `json:"window_days"`
+	AlertThresholds      []float64 `json:"alert_thresholds"`
+	AlertedThreshold     float64   `json:"alerted_threshold"`
+	BurnRateThreshold1h  float64   `json:"burn_rate_threshold_1h"`
+	BurnRateThreshold24h float64   `json:"burn_rate_threshold_24h"`
+	BurnAlerted1h        bool      `json:"burn_alerted_1h"`
+	BurnAlerted24h       bool      `json:"burn_alerted_24h"`
+	CreatedAt            time.Time `json:"created_at"`
+	OrgID                string    `json:"-"`
+}
+
+// SLOStatus is an SLO evaluated at EvaluatedAt. Budgets are in seconds of
+// downtime allowed over the part of the window with status history. Burn rates
+// compare the recent share of downtime with the share the target allows: 1
+// spends the budget exactly over the window, 10 ten times as fast.
+type SLOStatus struct {
+	SLO
+	AvailabilityPercent    *float64  `json:"availability_percent"`
+	ErrorBudgetSeconds     float64   `json:"error_budget_seconds"`
+	BudgetConsumedSeconds  float64   `json:"budget_consumed_seconds"`
+	BudgetConsumedPercent  float64   `json:"budget_consumed_percent"`
+	BudgetRemainingPercent float64   `json:"budget_remaining_percent"`
+	BurnRate1h             float64   `json:"burn_rate_1h"`
+	BurnRate24h            float64   `json:"burn_rate_24h"`
+	EvaluatedAt            time.Time `json:"evaluated_at"`
+}
+
+// SLOBurnEvent is sent when the consumed budget of an SLO crosses one of its
+// alert thresholds, or when the burn rate over Window reaches its threshold.
+// Threshold is a percentage of budget consumed in the first case and a burn
+// rate in the second.
+type SLOBurnEvent struct {
+	Window    string    `json:"window,omitempty"`
+	Threshold float64   `json:"threshold"`
+	Status    SLOStatus `json:"status"`
+}
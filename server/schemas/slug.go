@@ -0,0 +1,37 @@
+package Schemas
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	maxSlugLength = 63
+	// maxGeneratedSlugLength leaves room for a "-2" style suffix.
+	maxGeneratedSlugLength = 50
+)
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+	slugLetter    = regexp.MustCompile(`[a-z]`)
+)
+
+// ValidSlug reports whether slug is lowercase letters, digits and single
+// hyphens. Slugs need at least one letter so they never read as an ID.
+func ValidSlug(slug string) bool {
+	return len(slug) <= maxSlugLength && slugPattern.MatchString(slug) && slugLetter.MatchString(slug)
+}
+
+// Slugify derives a slug from a name, e.g. "Payments API (EU)" becomes
+// "payments-api-eu". Names without letters get a "service-" prefix.
+func Slugify(name string) string {
+	slug := strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if !slugLetter.MatchString(slug) {
+		slug = strings.Trim("service-"+slug, "-")
+	}
+	if len(slug) > maxGeneratedSlugLength {
+		slug = strings.TrimRight(slug[:maxGeneratedSlugLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,196 @@
+package Schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ServiceStatus is a value of the service_status enum.
+type ServiceStatus string
+
+const (
+	ServiceOperational      ServiceStatus = "operational"
+	ServiceUnderMaintenance ServiceStatus = "under maintenance"
+	ServiceDegraded         ServiceStatus = "degraded performance"
+	ServicePartialOutage    ServiceStatus = "partial outage"
+	ServiceMajorOutage      ServiceStatus = "major outage"
+)
+
+// ServiceStatuses are all service statuses, best first. Maintenance ranks
+// between operational and any degradation.
+var ServiceStatuses = []ServiceStatus{
+	ServiceOperational, ServiceUnderMaintenance, ServiceDegraded, ServicePartialOutage, ServiceMajorOutage,
+}
+
+func (s ServiceStatus) Valid() bool {
+	return slices.Contains(ServiceStatuses, s)
+}
+
+// Worse reports whether s is worse than other. Unknown statuses rank below
+// operational.
+func (s ServiceStatus) Worse(other ServiceStatus) bool {
+	return slices.Index(ServiceStatuses, s) > slices.Index(ServiceStatuses, other)
+}
+
+func (s *ServiceStatus) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if !ServiceStatus(raw).Valid() {
+		return NewInvalidServiceStatus(raw)
+	}
+	*s = ServiceStatus(raw)
+	return nil
+}
+
+// IncidentStatus is a value of the incident_status enum.
+type IncidentStatus string
+
+const (
+	IncidentInvestigating IncidentStatus = "investigating"
+	IncidentIdentified    IncidentStatus = "identified"
+	IncidentMonitoring    IncidentStatus = "monitoring"
+	IncidentResolved      IncidentStatus = "resolved"
+	IncidentMaintenance   IncidentStatus = "maintenance"
+	// incidentMaintenanceLegacy is a misspelling older clients send; it is
+	// read as IncidentMaintenance.
+	incidentMaintenanceLegacy IncidentStatus = "maintainence"
+)
+
+// IncidentStatuses are all incident statuses in lifecycle order.
+var IncidentStatuses = []IncidentStatus{
+	IncidentInvestigating, IncidentIdentified, IncidentMonitoring, IncidentResolved, IncidentMaintenance,
+}
+
+func (s IncidentStatus) Valid() bool {
+	return slices.Contains(IncidentStatuses, s)
+}
+
+func (s *IncidentStatus) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	status := IncidentStatus(raw)
+	if status == incidentMaintenanceLegacy {
+		status = IncidentMaintenance
+	}
+	if !status.Valid() {
+		return NewInvalidIncidentStatus(raw)
+	}
+	*s = status
+	return nil
+}
+
+// Impact is how badly an incident affects a linked service, or the status a
+// failing check puts its service in: one of ImpactLevels. Empty means unset.
+type Impact string
+
+func (i Impact) Valid() bool {
+	return ValidImpact(string(i))
+}
+
+func (i *Impact) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw != "" && !Impact(raw).Valid() {
+		return NewInvalidImpact(raw)
+	}
+	*i = Impact(raw)
+	return nil
+}
+
+// InvalidStatusError is returned when a request carries an unknown status or
+// impact.
+type InvalidStatusError struct {
+	Kind    string
+	Value   string
+	Allowed []string
+}
+
+func NewInvalidServiceStatus(value string) *InvalidStatusError {
+	return &InvalidStatusError{Kind: "service status", Value: value, Allowed: statusStrings(ServiceStatuses)}
+}
+
+func NewInvalidIncidentStatus(value string) *InvalidStatusError {
+	return &InvalidStatusError{Kind: "incident status", Value: value, Allowed: statusStrings(IncidentStatuses)}
+}
+
+func NewInvalidImpact(value string) *InvalidStatusError {
+	return &InvalidStatusError{Kind: "impact", Value: value, Allowed: ImpactLevels}
+}
+
+func (e *InvalidStatusError) Error() string {
+	return fmt.Sprintf("invalid %s %q: must be one of %s", e.Kind, e.Value, strings.Join(e.Allowed, ", "))
+}
+
+func statusStrings[S ~string](statuses []S) []string {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	return values
+}
+
+// StatusInfo describes a status for display.
+type StatusInfo struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Color string `json:"color"`
+}
+
+// StatusCatalog lists every status clients may show or send.
+type StatusCatalog struct {
+	ServiceStatuses  []StatusInfo `json:"service_statuses"`
+	IncidentStatuses []StatusInfo `json:"incident_statuses"`
+	ImpactLevels     []StatusInfo `json:"impact_levels"`
+}
+
+var serviceStatusInfo = map[ServiceStatus]StatusInfo{
+	ServiceOperational:      {Label: "Operational", Color: "#2fcc66"},
+	ServiceUnderMaintenance: {Label: "Under Maintenance", Color: "#3498db"},
+	ServiceDegraded:         {Label: "Degraded Performance", Color: "#f1c40f"},
+	ServicePartialOutage:    {Label: "Partial Outage", Color: "#e67e22"},
+	ServiceMajorOutage:      {Label: "Major Outage", Color: "#e74c3c"},
+}
+
+var incidentStatusInfo = map[IncidentStatus]StatusInfo{
+	IncidentInvestigating: {Label: "Investigating", Color: "#e74c3c"},
+	IncidentIdentified:    {Label: "Identified", Color: "#e67e22"},
+	IncidentMonitoring:    {Label: "Monitoring", Color: "#3498db"},
+	IncidentResolved:      {Label: "Resolved", Color: "#2fcc66"},
+	IncidentMaintenance:   {Label: "Maintenance", Color: "#3498db"},
+}
+
+// Info returns the display label and colour of the status.
+func (s ServiceStatus) Info() StatusInfo {
+	info := serviceStatusInfo[s]
+	info.Value = string(s)
+	return info
+}
+
+// Info returns the display label and colour of the status.
+func (s IncidentStatus) Info() StatusInfo {
+	info := incidentStatusInfo[s]
+	info.Value = string(s)
+	return info
+}
+
+func Statuses() StatusCatalog {
+	catalog := StatusCatalog{}
+	for _, status := range ServiceStatuses {
+		catalog.ServiceStatuses = append(catalog.ServiceStatuses, status.Info())
+	}
+	for _, status := range IncidentStatuses {
+		catalog.IncidentStatuses = append(catalog.IncidentStatuses, status.Info())
+	}
+	for _, impact := range ImpactLevels {
+		catalog.ImpactLevels = append(catalog.ImpactLevels, ServiceStatus(impact).Info())
+	}
+	return catalog
+}
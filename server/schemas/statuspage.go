@@ -0,0 +1,91 @@
+package Schemas
+
+import "time"
+
+// The types below follow the public Atlassian Statuspage v2 API so existing
+// integrations can read our status pages. Field names and values must not
+// change.
+
+type StatuspagePage struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	TimeZone  string    `json:"time_zone"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StatuspageStatus indicator is none, minor, major, critical or maintenance.
+type StatuspageStatus struct {
+	Indicator   string `json:"indicator"`
+	Description string `json:"description"`
+}
+
+// StatuspageComponent is a service, or a group when Group is set. Status is
+// operational, degraded_performance, partial_outage, major_outage or
+// under_maintenance.
+type StatuspageComponent struct {
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	Status             string    `json:"status"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	Position           int       `json:"position"`
+	Description        *string   `json:"description"`
+	Showcase           bool      `json:"showcase"`
+	StartDate          *string   `json:"start_date"`
+	GroupID            *string   `json:"group_id"`
+	PageID             string    `json:"page_id"`
+	Group              bool      `json:"group"`
+	OnlyShowIfDegraded bool      `json:"only_show_if_degraded"`
+	Components         []string  `json:"components,omitempty"`
+}
+
+type StatuspageIncidentUpdate struct {
+	ID                   string               `json:"id"`
+	Status               string               `json:"status"`
+	Body                 string               `json:"body"`
+	IncidentID           string               `json:"incident_id"`
+	CreatedAt            time.Time            `json:"created_at"`
+	UpdatedAt            time.Time            `json:"updated_at"`
+	DisplayAt            time.Time            `json:"display_at"`
+	AffectedComponents   []StatuspageAffected `json:"affected_components"`
+	DeliverNotifications bool                 `json:"deliver_notifications"`
+	CustomTweet          *string              `json:"custom_tweet"`
+	TweetID              *string              `json:"tweet_id"`
+}
+
+type StatuspageAffected struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	OldStatus string `json:"old_status"`
+	NewStatus string `json:"new_status"`
+}
+
+// StatuspageIncident is an incident or a scheduled maintenance; the
+// Scheduled fields are only meaningful for the latter. Impact is none, minor,
+// major, critical or maintenance.
+type StatuspageIncident struct {
+	ID              string                     `json:"id"`
+	Name            string                     `json:"name"`
+	Status          string                     `json:"status"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	UpdatedAt       time.Time                  `json:"updated_at"`
+	MonitoringAt    *time.Time                 `json:"monitoring_at"`
+	ResolvedAt      *time.Time                 `json:"resolved_at"`
+	Impact          string                     `json:"impact"`
+	Shortlink       string                     `json:"shortlink"`
+	StartedAt       time.Time                  `json:"started_at"`
+	PageID          string                     `json:"page_id"`
+	IncidentUpdates []StatuspageIncidentUpdate `json:"incident_updates"`
+	Components      []StatuspageComponent      `json:"components"`
+	ScheduledFor    *time.Time                 `json:"scheduled_for,omitempty"`
+	ScheduledUntil  *time.Time                 `json:"scheduled_until,omitempty"`
+}
+
+type StatuspageSummary struct {
+	Page                  StatuspagePage        `json:"page"`
+	Components            []StatuspageComponent `json:"components"`
+	Incidents             []StatuspageIncident  `json:"incidents"`
+	ScheduledMaintenances []StatuspageIncident  `json:"scheduled_maintenances"`
+	Status                StatuspageStatus      `json:"status"`
+}
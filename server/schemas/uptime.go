@@ -0,0 +1,38 @@
+package Schemas
+
+import "time"
+
+// StatusChange is one row of a service's status history: the service had
+// Status from ChangedAt until its next change.
+type StatusChange struct {
+	ServiceID int           `json:"service_id"`
+	Status    ServiceStatus `json:"status"`
+	ChangedAt time.Time     `json:"changed_at"`
+}
+
+// StatusTally is the time a service spent in each status over a period.
+// Worst is empty and UptimePercent nil when the period has no history.
+type StatusTally struct {
+	Seconds       map[ServiceStatus]float64 `json:"seconds"`
+	Worst         ServiceStatus             `json:"worst_status,omitempty"`
+	UptimePercent *float64                  `json:"uptime_percent"`
+}
+
+type UptimeDay struct {
+	Date string `json:"date"`
+	StatusTally
+}
+
+type ServiceUptime struct {
+	ServiceID   int    `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	StatusTally
+	Days []UptimeDay `json:"days"`
+}
+
+// UptimeReport covers [From, To) with one bar per UTC day.
+type UptimeReport struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Services []ServiceUptime `json:"services"`
+}
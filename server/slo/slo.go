@@ -0,0 +1,87 @@
+// Package slo evaluates service level objectives against service status
+// history and announces error budget burn.
+package slo
+
+import (
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/krnveersharma/Statuses/uptime"
+)
+
+// Window returns the range an SLO is evaluated over at now.
+func Window(o Schemas.SLO, now time.Time) (time.Time, time.Time) {
+	return now.Add(-time.Duration(o.WindowDays) * 24 * time.Hour), now
+}
+
+// Evaluate computes the budget and burn rates of an SLO at now. history is
+// the service's status changes covering the SLO window, as returned for uptime
+// reports.
+func Evaluate(o Schemas.SLO, history []Schemas.StatusChange, now time.Time) Schemas.SLOStatus {
+	status := Schemas.SLOStatus{SLO: o, EvaluatedAt: now, BudgetRemainingPercent: 100}
+	allowed := 1 - o.Target/100
+
+	from, to := Window(o, now)
+	tally := uptime.Tally(history, from, to, now)
+	known, down := downtime(tally)
+	status.AvailabilityPercent = tally.UptimePercent
+	status.ErrorBudgetSeconds = known * allowed
+	status.BudgetConsumedSeconds = down
+	if status.ErrorBudgetSeconds > 0 {
+		status.BudgetConsumedPercent = down / status.ErrorBudgetSeconds * 100
+		status.BudgetRemainingPercent = 100 - status.BudgetConsumedPercent
+	}
+
+	status.BurnRate1h = burnRate(history, now, time.Hour, allowed)
+	status.BurnRate24h = burnRate(history, now, 24*time.Hour, allowed)
+	return status
+}
+
+// CrossedThreshold returns the highest alert threshold the consumed budget
+// has reached, or 0 when it is below all of them.
+func CrossedThreshold(status Schemas.SLOStatus) float64 {
+	crossed := 0.0
+	for _, threshold := range status.AlertThresholds {
+		if status.BudgetConsumedPercent >= threshold {
+			crossed = max(crossed, threshold)
+		}
+	}
+	return crossed
+}
+
+// BurnRate returns the burn rate over window and the SLO's alert threshold
+// for it.
+func BurnRate(status Schemas.SLOStatus, window string) (float64, float64) {
+	if window == Schemas.SLOBurnWindow24h {
+		return status.BurnRate24h, status.BurnRateThreshold24h
+	}
+	return status.BurnRate1h, status.BurnRateThreshold1h
+}
+
+// Burning reports whether the burn rate over window has reached its alert
+// threshold. A zero threshold never fires.
+func Burning(status Schemas.SLOStatus, window string) bool {
+	rate, threshold := BurnRate(status, window)
+	return threshold > 0 && rate >= threshold
+}
+
+func burnRate(history []Schemas.StatusChange, now time.Time, lookback time.Duration, allowed float64) float64 {
+	known, down := downtime(uptime.Tally(history, now.Add(-lookback), now, now))
+	if known == 0 || allowed <= 0 {
+		return 0
+	}
+	return down / known / allowed
+}
+
+// downtime returns the seconds covered by a tally and how many of them were
+// spent down.
+func downtime(tally Schemas.StatusTally) (float64, float64) {
+	var known, down float64
+	for status, seconds := range tally.Seconds {
+		known += seconds
+		if !uptime.Up(status) {
+			down += seconds
+		}
+	}
+	return known, down
+}
@@ -0,0 +1,159 @@
+package slo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// now is the evaluation time of every test; history is laid out before it.
+var now = time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+// monthly is a 99.9% objective over 30 days, which allows 2592 seconds of
+// downtime when the whole window has history.
+var monthly = Schemas.SLO{Target: 99.9, WindowDays: 30, BurnRateThreshold1h: 14.4, BurnRateThreshold24h: 6}
+
+// outage returns a service that has been operational since before the
+// window, except for a major outage that started ago before now and lasted
+// for length. A zero length leaves the outage ongoing.
+func outage(ago, length time.Duration) []Schemas.StatusChange {
+	history := []Schemas.StatusChange{
+		{Status: Schemas.ServiceOperational, ChangedAt: now.AddDate(0, 0, -60)},
+		{Status: Schemas.ServiceMajorOutage, ChangedAt: now.Add(-ago)},
+	}
+	if length > 0 {
+		history = append(history, Schemas.StatusChange{Status: Schemas.ServiceOperational, ChangedAt: now.Add(-ago + length)})
+	}
+	return history
+}
+
+func expectClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestEvaluateWithoutDowntime(t *testing.T) {
+	history := []Schemas.StatusChange{{Status: Schemas.ServiceOperational, ChangedAt: now.AddDate(0, 0, -60)}}
+
+	status := Evaluate(monthly, history, now)
+	if !status.EvaluatedAt.Equal(now) {
+		t.Errorf("EvaluatedAt = %v, want %v", status.EvaluatedAt, now)
+	}
+	if status.AvailabilityPercent == nil || *status.AvailabilityPercent != 100 {
+		t.Errorf("AvailabilityPercent = %v, want 100", status.AvailabilityPercent)
+	}
+	expectClose(t, "ErrorBudgetSeconds", status.ErrorBudgetSeconds, 2592)
+	expectClose(t, "BudgetConsumedSeconds", status.BudgetConsumedSeconds, 0)
+	expectClose(t, "BudgetRemainingPercent", status.BudgetRemainingPercent, 100)
+	expectClose(t, "BurnRate1h", status.BurnRate1h, 0)
+	expectClose(t, "BurnRate24h", status.BurnRate24h, 0)
+}
+
+func TestEvaluateSpendsBudget(t *testing.T) {
+	// Twenty minutes down ten days ago uses 1200 of 2592 seconds and is
+	// outside both burn rate windows.
+	status := Evaluate(monthly, outage(10*24*time.Hour, 20*time.Minute), now)
+	expectClose(t, "BudgetConsumedSeconds", status.BudgetConsumedSeconds, 1200)
+	expectClose(t, "BudgetConsumedPercent", status.BudgetConsumedPercent, 1200.0/2592*100)
+	expectClose(t, "BudgetRemainingPercent", status.BudgetRemainingPercent, 100-1200.0/2592*100)
+	expectClose(t, "BurnRate1h", status.BurnRate1h, 0)
+	expectClose(t, "BurnRate24h", status.BurnRate24h, 0)
+}
+
+func TestEvaluateOverspentBudget(t *testing.T) {
+	// An hour and a half down is 5400 seconds, a little over twice the budget.
+	status := Evaluate(monthly, outage(5*24*time.Hour, 90*time.Minute), now)
+	expectClose(t, "BudgetConsumedPercent", status.BudgetConsumedPercent, 5400.0/2592*100)
+	if status.BudgetRemainingPercent >= 0 {
+		t.Errorf("BudgetRemainingPercent = %v, want below zero", status.BudgetRemainingPercent)
+	}
+}
+
+func TestEvaluateBurnRates(t *testing.T) {
+	// An ongoing outage of 30 minutes is half of the last hour down, 500 times
+	// the allowed 0.1%, and 1/48 of the last day.
+	status := Evaluate(monthly, outage(30*time.Minute, 0), now)
+	expectClose(t, "BudgetConsumedSeconds", status.BudgetConsumedSeconds, 1800)
+	expectClose(t, "BurnRate1h", status.BurnRate1h, 0.5/0.001)
+	expectClose(t, "BurnRate24h", status.BurnRate24h, 1.0/48/0.001)
+
+	// Once it has been over for an hour it only burns the day's budget.
+	status = Evaluate(monthly, outage(90*time.Minute, 30*time.Minute), now)
+	expectClose(t, "BurnRate1h", status.BurnRate1h, 0)
+	expectClose(t, "BurnRate24h", status.BurnRate24h, 1.0/48/0.001)
+}
+
+func TestEvaluateIgnoresMaintenance(t *testing.T) {
+	history := []Schemas.StatusChange{
+		{Status: Schemas.ServiceOperational, ChangedAt: now.AddDate(0, 0, -60)},
+		{Status: Schemas.ServiceUnderMaintenance, ChangedAt: now.Add(-3 * time.Hour)},
+		{Status: Schemas.ServiceOperational, ChangedAt: now.Add(-time.Hour)},
+	}
+
+	status := Evaluate(monthly, history, now)
+	expectClose(t, "BudgetConsumedSeconds", status.BudgetConsumedSeconds, 0)
+	expectClose(t, "BurnRate24h", status.BurnRate24h, 0)
+}
+
+func TestEvaluatePartialHistory(t *testing.T) {
+	// A service created three days ago only earns three days of budget.
+	history := []Schemas.StatusChange{{Status: Schemas.ServiceOperational, ChangedAt: now.AddDate(0, 0, -3)}}
+
+	status := Evaluate(monthly, history, now)
+	expectClose(t, "ErrorBudgetSeconds", status.ErrorBudgetSeconds, 3*86400*0.001)
+
+	status = Evaluate(monthly, nil, now)
+	if status.AvailabilityPercent != nil {
+		t.Errorf("AvailabilityPercent = %v, want nil", *status.AvailabilityPercent)
+	}
+	expectClose(t, "ErrorBudgetSeconds", status.ErrorBudgetSeconds, 0)
+	expectClose(t, "BudgetRemainingPercent", status.BudgetRemainingPercent, 100)
+}
+
+func TestCrossedThreshold(t *testing.T) {
+	thresholds := []float64{50, 75, 90, 100}
+	for consumed, expected := range map[float64]float64{
+		-10:   0,
+		49.99: 0,
+		50:    50,
+		89.9:  75,
+		150:   100,
+	} {
+		status := Schemas.SLOStatus{SLO: Schemas.SLO{AlertThresholds: thresholds}, BudgetConsumedPercent: consumed}
+		if got := CrossedThreshold(status); got != expected {
+			t.Errorf("CrossedThreshold() at %v%% consumed = %v, want %v", consumed, got, expected)
+		}
+	}
+
+	if got := CrossedThreshold(Schemas.SLOStatus{BudgetConsumedPercent: 100}); got != 0 {
+		t.Errorf("CrossedThreshold() without thresholds = %v, want 0", got)
+	}
+}
+
+func TestBurning(t *testing.T) {
+	status := Evaluate(monthly, outage(30*time.Minute, 0), now)
+	if !Burning(status, Schemas.SLOBurnWindow1h) {
+		t.Errorf("1h burn rate %v does not reach %v", status.BurnRate1h, monthly.BurnRateThreshold1h)
+	}
+	if !Burning(status, Schemas.SLOBurnWindow24h) {
+		t.Errorf("24h burn rate %v does not reach %v", status.BurnRate24h, monthly.BurnRateThreshold24h)
+	}
+
+	// A one minute blip burns 16.7x over the hour but under 0.7x over the day.
+	status = Evaluate(monthly, outage(10*time.Minute, time.Minute), now)
+	if !Burning(status, Schemas.SLOBurnWindow1h) {
+		t.Errorf("1h burn rate %v does not reach %v", status.BurnRate1h, monthly.BurnRateThreshold1h)
+	}
+	if Burning(status, Schemas.SLOBurnWindow24h) {
+		t.Errorf("24h burn rate %v reaches %v", status.BurnRate24h, monthly.BurnRateThreshold24h)
+	}
+
+	status.BurnRateThreshold1h = 0
+	if Burning(status, Schemas.SLOBurnWindow1h) {
+		t.Error("a zero threshold fired")
+	}
+}
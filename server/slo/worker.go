@@ -0,0 +1,140 @@
+package slo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	dbrequests "github.com/krnveersharma/Statuses/dbRequests"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+	"github.com/krnveersharma/Statuses/websocketsHandler"
+)
+
+// Evaluator periodically evaluates every SLO and sends a burn event when its
+// consumed budget crosses a new alert threshold or a burn rate reaches its
+// threshold.
+type Evaluator struct {
+	DB       *sql.DB
+	Interval time.Duration
+}
+
+func NewEvaluator(db *sql.DB, interval time.Duration) *Evaluator {
+	return &Evaluator{DB: db, Interval: interval}
+}
+
+// Run evaluates SLOs every Interval until ctx is cancelled.
+func (e *Evaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.evaluate(ctx, time.Now()); err != nil {
+			log.Printf("[SLO] %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Evaluator) evaluate(ctx context.Context, now time.Time) error {
+	slos, err := dbrequests.GetAllSLOs(ctx, e.DB)
+	if err != nil {
+		return fmt.Errorf("fetching SLOs: %w", err)
+	}
+
+	// SLOs come grouped by org, so history is loaded once per org.
+	for start := 0; start < len(slos); {
+		end := start
+		for end < len(slos) && slos[end].OrgID == slos[start].OrgID {
+			end++
+		}
+		e.evaluateOrg(ctx, slos[start:end], now)
+		start = end
+	}
+	return nil
+}
+
+func (e *Evaluator) evaluateOrg(ctx context.Context, slos []Schemas.SLO, now time.Time) {
+	from := now
+	for _, o := range slos {
+		if start, _ := Window(o, now); start.Before(from) {
+			from = start
+		}
+	}
+
+	orgID := slos[0].OrgID
+	history, err := dbrequests.GetStatusHistoryForOrg(e.DB, orgID, from, now)
+	if err != nil {
+		log.Printf("[SLO] Failed to fetch status history for org %s: %v\n", orgID, err)
+		return
+	}
+
+	for _, o := range slos {
+		status := Evaluate(o, history[o.ServiceID], now)
+		e.alertBudget(ctx, o, status)
+		for _, window := range Schemas.SLOBurnWindows {
+			e.alertBurnRate(ctx, o, status, window)
+		}
+	}
+}
+
+// alertBudget sends a burn event when the consumed budget crosses a threshold
+// above the one last announced.
+func (e *Evaluator) alertBudget(ctx context.Context, o Schemas.SLO, status Schemas.SLOStatus) {
+	crossed := CrossedThreshold(status)
+	if crossed == o.AlertedThreshold {
+		return
+	}
+
+	// Lowering the mark as bad time leaves the window is silent; it only
+	// lets the threshold be announced again.
+	moved, err := dbrequests.SetSLOAlertedThreshold(ctx, e.DB, o.ID, o.AlertedThreshold, crossed)
+	if err != nil {
+		log.Printf("[SLO] Failed to record threshold for SLO %d: %v\n", o.ID, err)
+		return
+	}
+	if !moved || crossed < o.AlertedThreshold {
+		return
+	}
+
+	status.AlertedThreshold = crossed
+	log.Printf("[SLO] SLO %d of service %d has used %.1f%% of its error budget\n", o.ID, o.ServiceID, status.BudgetConsumedPercent)
+	websocketsHandler.SLOBudgetBurn(o.OrgID, Schemas.SLOBurnEvent{Threshold: crossed, Status: status})
+}
+
+// alertBurnRate sends a burn event when the burn rate over window reaches its
+// threshold. The alert re-arms silently once the rate falls below it.
+func (e *Evaluator) alertBurnRate(ctx context.Context, o Schemas.SLO, status Schemas.SLOStatus, window string) {
+	alerted := o.BurnAlerted1h
+	if window == Schemas.SLOBurnWindow24h {
+		alerted = o.BurnAlerted24h
+	}
+	burning := Burning(status, window)
+	if burning == alerted {
+		return
+	}
+
+	moved, err := dbrequests.SetSLOBurnAlerted(ctx, e.DB, o.ID, window, alerted, burning)
+	if err != nil {
+		log.Printf("[SLO] Failed to record %s burn alert for SLO %d: %v\n", window, o.ID, err)
+		return
+	}
+	if !moved || !burning {
+		return
+	}
+
+	if window == Schemas.SLOBurnWindow24h {
+		status.BurnAlerted24h = true
+	} else {
+		status.BurnAlerted1h = true
+	}
+	rate, threshold := BurnRate(status, window)
+	log.Printf("[SLO] SLO %d of service %d is burning its error budget %.1fx over %s\n", o.ID, o.ServiceID, rate, window)
+	websocketsHandler.SLOBudgetBurn(o.OrgID, Schemas.SLOBurnEvent{Window: window, Threshold: threshold, Status: status})
+}
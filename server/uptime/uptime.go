@@ -0,0 +1,79 @@
+// Package uptime turns service status history into time spent per status,
+// uptime percentages and per-day bars.
+package uptime
+
+import (
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+// Up reports whether time spent in status counts as available. Maintenance
+// and degraded performance count as up; partial and major outages are down.
+func Up(status Schemas.ServiceStatus) bool {
+	return status == Schemas.ServiceOperational || status == Schemas.ServiceUnderMaintenance || status == Schemas.ServiceDegraded
+}
+
+// Tally sums the time spent in each status within [from, to). history must be
+// one service's changes ordered by time; the entry in effect at from may
+// precede it. Time before the first entry and after now is not counted.
+func Tally(history []Schemas.StatusChange, from, to, now time.Time) Schemas.StatusTally {
+	tally := Schemas.StatusTally{Seconds: map[Schemas.ServiceStatus]float64{}}
+	to = minTime(to, now)
+
+	var known, up float64
+	for i, change := range history {
+		start := change.ChangedAt
+		end := to
+		if i+1 < len(history) {
+			end = history[i+1].ChangedAt
+		}
+		start, end = maxTime(start, from), minTime(end, to)
+		if !start.Before(end) {
+			continue
+		}
+
+		seconds := end.Sub(start).Seconds()
+		tally.Seconds[change.Status] += seconds
+		known += seconds
+		if Up(change.Status) {
+			up += seconds
+		}
+		if change.Status.Worse(tally.Worst) {
+			tally.Worst = change.Status
+		}
+	}
+
+	if known > 0 {
+		percent := up / known * 100
+		tally.UptimePercent = &percent
+	}
+	return tally
+}
+
+// Days tallies history per UTC calendar day overlapping [from, to), counting
+// up to now.
+func Days(history []Schemas.StatusChange, from, to, now time.Time) []Schemas.UptimeDay {
+	var days []Schemas.UptimeDay
+	for day := from.UTC().Truncate(24 * time.Hour); day.Before(to); day = day.Add(24 * time.Hour) {
+		days = append(days, Schemas.UptimeDay{
+			Date:        day.Format(time.DateOnly),
+			StatusTally: Tally(history, maxTime(day, from), minTime(day.Add(24*time.Hour), to), now),
+		})
+	}
+	return days
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,190 @@
+package uptime
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+var base = time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+func at(hours float64) time.Time {
+	return base.Add(time.Duration(hours * float64(time.Hour)))
+}
+
+func change(hours float64, status Schemas.ServiceStatus) Schemas.StatusChange {
+	return Schemas.StatusChange{Status: status, ChangedAt: at(hours)}
+}
+
+func TestTally(t *testing.T) {
+	history := []Schemas.StatusChange{
+		change(-5, Schemas.ServiceOperational),
+		change(2, Schemas.ServiceMajorOutage),
+		change(3, Schemas.ServiceUnderMaintenance),
+		change(4, Schemas.ServiceDegraded),
+		change(6, Schemas.ServiceOperational),
+	}
+
+	tests := []struct {
+		name    string
+		history []Schemas.StatusChange
+		from    time.Time
+		to      time.Time
+		now     time.Time
+		seconds map[Schemas.ServiceStatus]float64
+		uptime  float64
+		worst   Schemas.ServiceStatus
+	}{
+		{
+			name: "entry in effect at from counts from from", history: history,
+			from: at(0), to: at(2), now: at(24),
+			seconds: map[Schemas.ServiceStatus]float64{Schemas.ServiceOperational: 2 * 3600},
+			uptime:  100, worst: Schemas.ServiceOperational,
+		},
+		{
+			name: "outage counts as down, maintenance and degraded as up", history: history,
+			from: at(0), to: at(8), now: at(24),
+			seconds: map[Schemas.ServiceStatus]float64{
+				Schemas.ServiceOperational:      4 * 3600,
+				Schemas.ServiceMajorOutage:      3600,
+				Schemas.ServiceUnderMaintenance: 3600,
+				Schemas.ServiceDegraded:         2 * 3600,
+			},
+			uptime: 87.5, worst: Schemas.ServiceMajorOutage,
+		},
+		{
+			name: "change exactly at to is not counted", history: history,
+			from: at(1), to: at(2), now: at(24),
+			seconds: map[Schemas.ServiceStatus]float64{Schemas.ServiceOperational: 3600},
+			uptime:  100, worst: Schemas.ServiceOperational,
+		},
+		{
+			name: "change exactly at from counts", history: history,
+			from: at(2), to: at(3), now: at(24),
+			seconds: map[Schemas.ServiceStatus]float64{Schemas.ServiceMajorOutage: 3600},
+			uptime:  0, worst: Schemas.ServiceMajorOutage,
+		},
+		{
+			name: "clamped to now", history: history,
+			from: at(0), to: at(48), now: at(2.5),
+			seconds: map[Schemas.ServiceStatus]float64{Schemas.ServiceOperational: 2 * 3600, Schemas.ServiceMajorOutage: 1800},
+			uptime:  80, worst: Schemas.ServiceMajorOutage,
+		},
+		{
+			name: "time before the first entry is not counted", history: history[1:],
+			from: at(0), to: at(3), now: at(24),
+			seconds: map[Schemas.ServiceStatus]float64{Schemas.ServiceMajorOutage: 3600},
+			uptime:  0, worst: Schemas.ServiceMajorOutage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tally := Tally(tt.history, tt.from, tt.to, tt.now)
+			if len(tally.Seconds) != len(tt.seconds) {
+				t.Errorf("Seconds = %v, want %v", tally.Seconds, tt.seconds)
+			}
+			for status, seconds := range tt.seconds {
+				if tally.Seconds[status] != seconds {
+					t.Errorf("Seconds[%s] = %v, want %v", status, tally.Seconds[status], seconds)
+				}
+			}
+			if tally.UptimePercent == nil || math.Abs(*tally.UptimePercent-tt.uptime) > 1e-9 {
+				t.Errorf("UptimePercent = %v, want %v", tally.UptimePercent, tt.uptime)
+			}
+			if tally.Worst != tt.worst {
+				t.Errorf("Worst = %q, want %q", tally.Worst, tt.worst)
+			}
+		})
+	}
+}
+
+func TestTallyWithoutHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []Schemas.StatusChange
+		from    time.Time
+		to      time.Time
+		now     time.Time
+	}{
+		{"no changes", nil, at(0), at(24), at(48)},
+		{"range in the future", []Schemas.StatusChange{change(0, Schemas.ServiceOperational)}, at(10), at(20), at(5)},
+		{"range before the first change", []Schemas.StatusChange{change(10, Schemas.ServiceOperational)}, at(0), at(5), at(24)},
+		{"empty range", []Schemas.StatusChange{change(0, Schemas.ServiceOperational)}, at(5), at(5), at(24)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tally := Tally(tt.history, tt.from, tt.to, tt.now)
+			if tally.UptimePercent != nil {
+				t.Errorf("UptimePercent = %v, want nil", *tally.UptimePercent)
+			}
+			if len(tally.Seconds) != 0 {
+				t.Errorf("Seconds = %v, want none", tally.Seconds)
+			}
+		})
+	}
+}
+
+func TestDays(t *testing.T) {
+	history := []Schemas.StatusChange{
+		change(-30, Schemas.ServiceOperational),
+		change(30, Schemas.ServicePartialOutage),
+		change(36, Schemas.ServiceOperational),
+	}
+
+	tests := []struct {
+		name   string
+		from   time.Time
+		to     time.Time
+		now    time.Time
+		dates  []string
+		known  []float64
+		uptime []float64
+	}{
+		{
+			name: "whole days", from: at(0), to: at(72), now: at(100),
+			dates:  []string{"2026-03-10", "2026-03-11", "2026-03-12"},
+			known:  []float64{24 * 3600, 24 * 3600, 24 * 3600},
+			uptime: []float64{100, 75, 100},
+		},
+		{
+			name: "partial first and last day", from: at(12), to: at(30), now: at(100),
+			dates:  []string{"2026-03-10", "2026-03-11"},
+			known:  []float64{12 * 3600, 6 * 3600},
+			uptime: []float64{100, 100},
+		},
+		{
+			name: "today stops at now", from: at(24), to: at(48), now: at(33),
+			dates:  []string{"2026-03-11"},
+			known:  []float64{9 * 3600},
+			uptime: []float64{100 * 6 / 9.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := Days(history, tt.from, tt.to, tt.now)
+			if len(days) != len(tt.dates) {
+				t.Fatalf("got %d days, want %d", len(days), len(tt.dates))
+			}
+			for i, day := range days {
+				if day.Date != tt.dates[i] {
+					t.Errorf("day %d: Date = %s, want %s", i, day.Date, tt.dates[i])
+				}
+				known := 0.0
+				for _, seconds := range day.Seconds {
+					known += seconds
+				}
+				if known != tt.known[i] {
+					t.Errorf("day %d: %v seconds known, want %v", i, known, tt.known[i])
+				}
+				if day.UptimePercent == nil || math.Abs(*day.UptimePercent-tt.uptime[i]) > 1e-9 {
+					t.Errorf("day %d: UptimePercent = %v, want %v", i, day.UptimePercent, tt.uptime[i])
+				}
+			}
+		})
+	}
+}
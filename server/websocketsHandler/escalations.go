@@ -0,0 +1,16 @@
+package websocketsHandler
+
+import (
+	"encoding/json"
+
+	"github.com/krnveersharma/Statuses/realtime"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+func IncidentEscalated(orgId string, event Schemas.EscalationEvent) {
+	msg, _ := json.Marshal(map[string]interface{}{
+		"type":       orgId + "_incident_escalated_" + event.IncidentID,
+		"escalation": event,
+	})
+	realtime.Broadcast(msg)
+}
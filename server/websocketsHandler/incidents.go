@@ -29,3 +29,27 @@ func DeleteIncident(incidentId, orgId string) {
 	})
 	realtime.Broadcast(msg)
 }
+
+func MergeIncidents(targetId, orgId string, mergedIds []string, target Schemas.EditInstance) {
+	msg, _ := json.Marshal(map[string]interface{}{
+		"type":       orgId + "_incident_merged_" + targetId,
+		"incident":   target,
+		"merged_ids": mergedIds,
+	})
+	realtime.Broadcast(msg)
+}
+
+// AddIncidentNote announces a new timeline entry. The websocket is not
+// authenticated, so internal notes go out without their content and clients
+// refetch the incident through the authenticated API.
+func AddIncidentNote(incidentId, orgId string, update Schemas.IncidentUpdateData) {
+	event := map[string]interface{}{
+		"type":       orgId + "_incident_note_added_" + incidentId,
+		"visibility": update.Visibility,
+	}
+	if update.Visibility == Schemas.VisibilityPublic {
+		event["update"] = update
+	}
+	msg, _ := json.Marshal(event)
+	realtime.Broadcast(msg)
+}
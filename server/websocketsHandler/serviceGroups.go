@@ -0,0 +1,31 @@
+package websocketsHandler
+
+import (
+	"encoding/json"
+
+	"github.com/krnveersharma/Statuses/realtime"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+func CreateServiceGroup(orgId string, group Schemas.ServiceGroup) {
+	msg, _ := json.Marshal(map[string]interface{}{
+		"type":  orgId + "_service_group_created",
+		"group": group,
+	})
+	realtime.Broadcast(msg)
+}
+
+func UpdateServiceGroup(groupId, orgId string, group Schemas.ServiceGroup) {
+	msg, _ := json.Marshal(map[string]interface{}{
+		"type":  orgId + "_service_group_updated_" + groupId,
+		"group": group,
+	})
+	realtime.Broadcast(msg)
+}
+
+func DeleteServiceGroup(groupId, orgId string) {
+	msg, _ := json.Marshal(map[string]interface{}{
+		"type": orgId + "_service_group_deleted_" + groupId,
+	})
+	realtime.Broadcast(msg)
+}
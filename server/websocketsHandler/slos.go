@@ -0,0 +1,17 @@
+package websocketsHandler
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/krnveersharma/Statuses/realtime"
+	Schemas "github.com/krnveersharma/Statuses/schemas"
+)
+
+func SLOBudgetBurn(orgId string, event Schemas.SLOBurnEvent) {
+	msg, _ := json.Marshal(map[string]interface{}{
+		"type": orgId + "_slo_budget_burn_" + strconv.Itoa(event.Status.ServiceID),
+		"burn": event,
+	})
+	realtime.Broadcast(msg)
+}